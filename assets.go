@@ -0,0 +1,28 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+)
+
+// The default snippets, templates, blacklists and overrides are compiled
+// into the binary so that the generator works no matter where it was
+// installed.
+//
+//go:embed snippets templates blacklist overrides
+var assets embed.FS
+
+// Assets returns the directory at path if it is set, or the embedded
+// directory with the given name otherwise.
+func Assets(path, name string) fs.FS {
+	if path != "" {
+		return os.DirFS(path)
+	}
+	sub, err := fs.Sub(assets, name)
+	if err != nil {
+		// can only happen if name isn't a valid path
+		panic(err)
+	}
+	return sub
+}
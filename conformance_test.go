@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// conformanceNamespaces are the test namespaces gobject-introspection ships
+// for exercising bindings, which between them cover every kind of argument.
+var conformanceNamespaces = []string{"GIMarshallingTests", "Regress"}
+
+// marshalCase is one cell of the conformance matrix.
+type marshalCase struct {
+	Type      string // a type tag, or "interface:" and the kind of type
+	Direction string // in, out, inout or return
+	Transfer  string
+}
+
+func (c marshalCase) String() string {
+	return c.Type + "/" + c.Direction + "/" + c.Transfer
+}
+
+type caseResult struct {
+	Pass      bool // whether the generator can marshal it
+	Functions int  // how many functions have such an argument
+	Generated int  // how many of those get generated
+}
+
+// TestConformance measures how much of the type system the generator
+// handles. It sorts the arguments and return values of the functions of
+// the conformance namespaces by type, direction and transfer, with a
+// subtest for each combination, which fails if the generator can't marshal
+// it yet. It then generates and compiles the namespaces, and calls the
+// wrappers of wrapperChecks, checking what they return:
+//
+//	go test -run TestConformance -v -typelibdir /usr/lib/gobject-introspection/tests
+//
+// Their typelibs are only installed with the tests of gobject-introspection,
+// so the test is skipped without them. -gir, -typelibdir and -libdir work
+// as they do for generation; compiling needs the .gir files, since only
+// they say which headers to include, and CGO_CFLAGS and CGO_LDFLAGS
+// pointing at the headers and libraries of the test namespaces.
+func TestConformance(t *testing.T) {
+	prependSearchPaths()
+	namespaces := make(model.Namespaces)
+	var order []string
+	seen := make(map[string]bool)
+	for _, name := range conformanceNamespaces {
+		loaded, namespace, err := loadNamespaces(name)
+		if err != nil {
+			t.Skip(name + " isn't installed: " + err.Error())
+		}
+		for _, ns := range loaded {
+			if _, ok := namespaces[ns.Name]; !ok {
+				namespaces[ns.Name] = ns
+			}
+		}
+		for _, dep := range loaded.DependencyOrder(namespace) {
+			if !seen[dep] {
+				seen[dep] = true
+				order = append(order, dep)
+			}
+		}
+	}
+
+	results := make(map[marshalCase]*caseResult)
+	for _, name := range conformanceNamespaces {
+		for _, fn := range callables(namespaces[name]) {
+			recordCases(results, namespaces, fn)
+		}
+	}
+
+	cases := make([]marshalCase, 0, len(results))
+	for c := range results {
+		cases = append(cases, c)
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].String() < cases[j].String() })
+	passed := 0
+	for _, c := range cases {
+		result := results[c]
+		if result.Pass {
+			passed++
+		}
+		t.Run(c.String(), func(t *testing.T) {
+			if !result.Pass {
+				t.Fatalf("can't be marshalled; used by %d functions", result.Functions)
+			}
+			t.Logf("%d of the %d functions using it are generated", result.Generated, result.Functions)
+		})
+	}
+	t.Logf("%d of %d cases can be marshalled", passed, len(cases))
+
+	t.Run("calls", func(t *testing.T) {
+		for _, name := range conformanceNamespaces {
+			if len(namespaces[name].CIncludes) == 0 {
+				t.Skip(name + " was read from a typelib, which doesn't say which headers to include; use -gir")
+			}
+		}
+		testWrappers(t, namespaces, order)
+	})
+}
+
+// wrapperChecks are functions of the conformance namespaces that return a
+// known value, the Go source of the arguments to call them with, and what
+// they should return.
+var wrapperChecks = []struct {
+	namespace string
+	function  string
+	args      string
+	want      interface{}
+}{
+	{"GIMarshallingTests", "boolean_return_true", "", true},
+	{"GIMarshallingTests", "boolean_return_false", "", false},
+	{"GIMarshallingTests", "int8_return_max", "", int8(math.MaxInt8)},
+	{"GIMarshallingTests", "int8_return_min", "", int8(math.MinInt8)},
+	{"GIMarshallingTests", "uint8_return", "", uint8(math.MaxUint8)},
+	{"GIMarshallingTests", "int16_return_max", "", int16(math.MaxInt16)},
+	{"GIMarshallingTests", "int16_return_min", "", int16(math.MinInt16)},
+	{"GIMarshallingTests", "uint16_return", "", uint16(math.MaxUint16)},
+	{"GIMarshallingTests", "int32_return_max", "", int32(math.MaxInt32)},
+	{"GIMarshallingTests", "int32_return_min", "", int32(math.MinInt32)},
+	{"GIMarshallingTests", "uint32_return", "", uint32(math.MaxUint32)},
+	{"GIMarshallingTests", "int64_return_max", "", int64(math.MaxInt64)},
+	{"GIMarshallingTests", "int64_return_min", "", int64(math.MinInt64)},
+	{"GIMarshallingTests", "uint64_return", "", uint64(math.MaxUint64)},
+	{"GIMarshallingTests", "float_return", "", float32(math.MaxFloat32)},
+	{"GIMarshallingTests", "double_return", "", float64(math.MaxFloat64)},
+	{"GIMarshallingTests", "utf8_none_return", "", "const ♥ utf8"},
+	{"GIMarshallingTests", "utf8_full_return", "", "const ♥ utf8"},
+	{"Regress", "test_boolean", "true", true},
+	{"Regress", "test_int8", "-8", int8(-8)},
+	{"Regress", "test_uint8", "8", uint8(8)},
+	{"Regress", "test_int16", "-16", int16(-16)},
+	{"Regress", "test_uint16", "16", uint16(16)},
+	{"Regress", "test_int32", "-32", int32(-32)},
+	{"Regress", "test_uint32", "32", uint32(32)},
+	{"Regress", "test_int64", "-64", int64(-64)},
+	{"Regress", "test_uint64", "64", uint64(64)},
+	{"Regress", "test_float", "0.5", float32(0.5)},
+	{"Regress", "test_double", "0.25", float64(0.25)},
+	{"Regress", "test_utf8_const_return", "", "const ♥ utf8"},
+}
+
+// testWrappers generates the namespaces in order, and builds and runs a
+// program that calls the generated wrappers of wrapperChecks and prints
+// what each returned, with a subtest per check comparing it against what
+// it should be. A check whose function wasn't generated fails.
+func testWrappers(t *testing.T, namespaces model.Namespaces, order []string) {
+	g := newGenerator(t, namespaces)
+	files, report, err := g.Generate(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound := make(map[string]bool)
+	for _, ns := range report.Namespaces {
+		for _, symbol := range ns.Bound {
+			bound[symbol.Name] = true
+		}
+	}
+
+	var calls bytes.Buffer
+	var imports []string
+	imported := make(map[string]bool)
+	for _, check := range wrapperChecks {
+		name := model.QualifiedName(check.namespace, check.function)
+		if !bound[name] {
+			continue
+		}
+		for _, fn := range namespaces[check.namespace].Functions {
+			if fn.Name != check.function {
+				continue
+			}
+			pkg := strings.ToLower(check.namespace)
+			if !imported[pkg] {
+				imported[pkg] = true
+				imports = append(imports, path.Join(g.Module, pkg))
+			}
+			fmt.Fprintf(&calls, "\treport(%q, %s.%s(%s))\n", name, pkg, g.names.Function(check.namespace, fn), check.args)
+		}
+	}
+	var program bytes.Buffer
+	program.WriteString("package main\n\nimport (\n\t\"fmt\"\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&program, "\t%q\n", imp)
+	}
+	program.WriteString(")\n\nfunc report(name string, got interface{}) {\n\tfmt.Printf(\"%s\\t%T(%#v)\\n\", name, got, got)\n}\n\nfunc main() {\n")
+	program.Write(calls.Bytes())
+	program.WriteString("}\n")
+	files[path.Join("conformance", "main.go")] = program.Bytes()
+
+	out := t.TempDir()
+	if err := WriteGoMod(out, g.Module); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if _, err := WriteFile(out, name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	output, err := goCmd(out, "run", "./conformance").Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			output = exit.Stderr
+		}
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+	got := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if i := strings.IndexByte(line, '\t'); i >= 0 {
+			got[line[:i]] = line[i+1:]
+		}
+	}
+
+	for _, check := range wrapperChecks {
+		check := check
+		name := model.QualifiedName(check.namespace, check.function)
+		t.Run(name, func(t *testing.T) {
+			if !bound[name] {
+				t.Fatal("wasn't generated")
+			}
+			want := fmt.Sprintf("%T(%#v)", check.want, check.want)
+			if got[name] != want {
+				t.Errorf("got %s, want %s", got[name], want)
+			}
+		})
+	}
+}
+
+// callables returns every function of ns, including methods, leaving out
+// those that can't be bound at all.
+func callables(ns *model.Namespace) []*model.Callable {
+	var fns []*model.Callable
+	add := func(list []*model.Callable) {
+		for _, fn := range list {
+			if fn.Unintrospectable == "" {
+				fns = append(fns, fn)
+			}
+		}
+	}
+	add(ns.Functions)
+	for _, obj := range ns.Classes {
+		add(obj.Methods)
+	}
+	for _, iface := range ns.Interfaces {
+		add(iface.Methods)
+	}
+	return fns
+}
+
+// recordCases adds the arguments and return value of fn to results. Each
+// case is counted once per function, and passes if a function with only
+// that argument could be generated.
+func recordCases(results map[marshalCase]*caseResult, namespaces model.Namespaces, fn *model.Callable) {
+	_, _, _, _, err := readParams(fn, nil)
+	generated := err == nil
+
+	seen := make(map[marshalCase]bool)
+	add := func(c marshalCase, single *model.Callable) {
+		if seen[c] {
+			return
+		}
+		seen[c] = true
+		result, ok := results[c]
+		if !ok {
+			_, _, _, _, err := readParams(single, nil)
+			result = &caseResult{Pass: err == nil}
+			results[c] = result
+		}
+		result.Functions++
+		if generated {
+			result.Generated++
+		}
+	}
+
+	void := &model.TypeRef{Tag: model.VoidTag}
+	if returnsValue(fn.Return) {
+		c := marshalCase{caseType(namespaces, fn.Return), "return", fn.ReturnTransfer.String()}
+		add(c, &model.Callable{Return: fn.Return, ReturnTransfer: fn.ReturnTransfer})
+	}
+	for _, param := range fn.Params {
+		c := marshalCase{caseType(namespaces, param.Type), param.Direction.String(), param.Transfer.String()}
+		add(c, &model.Callable{Return: void, Params: []*model.Param{param}})
+	}
+}
+
+// caseType names the type of an argument for the matrix. Interfaces are
+// told apart by what kind of type they refer to.
+func caseType(namespaces model.Namespaces, typ *model.TypeRef) string {
+	if typ.Tag == model.VoidTag && typ.Pointer {
+		return "gpointer"
+	}
+	if typ.Tag != model.InterfaceTag {
+		return typ.Tag.String()
+	}
+	switch {
+	case namespaces.Enum(typ.Interface) != nil:
+		return "interface:enum"
+	case namespaces.Class(typ.Interface) != nil:
+		return "interface:object"
+	case namespaces.Interface(typ.Interface) != nil:
+		return "interface:interface"
+	}
+	return "interface:other"
+}
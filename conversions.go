@@ -1,207 +0,0 @@
-package main
-
-/*
-#cgo pkg-config: glib-2.0
-#include <glib.h>
-
-char *from_gchar(gchar *str) { return (char*)str; }
-gchar *to_gchar(char *str) { return (gchar*)str; }
-
-// bitflag support
-gboolean and(gint flags, gint position) {
-	return flags & position;
-}
-*/
-import "C"
-import (
-	"container/list"
-	"reflect"
-)
-
-func GoBool(b C.gboolean) bool {
-	if b == C.gboolean(0) {
-		return false
-	}
-	return true
-}
-
-func GlibBool(b bool) C.gboolean {
-	if b {
-		return C.gboolean(1)
-	}
-	return C.gboolean(0)
-}
-
-func GoChar(c C.gchar) int8 {
-	return int8(c)
-}
-
-func GlibChar(i int8) C.gchar {
-	return C.gchar(i)
-}
-
-func GoUChar(c C.guchar) uint {
-	return uint(c)
-}
-
-func GlibUChar(i uint) C.guchar {
-	return C.guchar(i)
-}
-
-func GoInt(i C.gint) int {
-	return int(i)
-}
-
-func GlibInt(i int) C.gint {
-	return C.gint(i)
-}
-
-func GoUInt(i C.guint) uint {
-	return uint(i)
-}
-
-func GlibUInt(i uint) C.guint {
-	return C.guint(i)
-}
-
-func GoInt8(i C.gint8) int8 {
-	return int8(i)
-}
-
-func GlibInt8(i int8) C.gint8 {
-	return C.gint8(i)
-}
-
-func GoUInt8(i C.guint8) uint8 {
-	return uint8(i)
-}
-
-func GlibUInt8(i uint8) C.guint8 {
-	return C.guint8(i)
-}
-
-func GoInt16(i C.gint16) int16 {
-	return int16(i)
-}
-
-func GlibInt16(i int16) C.gint16 {
-	return C.gint16(i)
-}
-
-func GoUInt16(i C.guint16) uint16 {
-	return uint16(i)
-}
-
-func GlibUInt16(i uint16) C.guint16 {
-	return C.guint16(i)
-}
-
-func GoInt32(i C.gint32) int32 {
-	return int32(i)
-}
-
-func GlibInt32(i int32) C.gint32 {
-	return C.gint32(i)
-}
-
-func GoUInt32(i C.guint32) uint32 {
-	return uint32(i)
-}
-
-func GlibUInt32(i uint32) C.guint32 {
-	return C.guint32(i)
-}
-
-func GoInt64(i C.gint64) int64 {
-	return int64(i)
-}
-
-func GlibInt64(i int64) C.gint64 {
-	return C.gint64(i)
-}
-
-func GoUInt64(i C.guint64) uint64 {
-	return uint64(i)
-}
-
-func GlibUInt64(i uint64) C.guint64 {
-	return C.guint64(i)
-}
-
-func GoShort(s C.gshort) int16 {
-	return int16(s)
-}
-
-func GlibShort(s int16) C.gshort {
-	return C.gshort(s)
-}
-
-func GoUShort(s C.gushort) uint16 {
-	return uint16(s)
-}
-
-func GlibUShort(s uint16) C.gushort {
-	return C.gushort(s)
-}
-
-func GoLong(l C.glong) int64 {
-	return int64(l)
-}
-
-func GlibLong(l int64) C.glong {
-	return C.glong(l)
-}
-
-func GoULong(l C.gulong) uint64 {
-	return uint64(l)
-}
-
-func GlibULong(l uint64) C.gulong {
-	return C.gulong(l)
-}
-
-// TODO: gint8, gint16, etc.
-
-func GoFloat(f C.gfloat) float32 {
-	return float32(f)
-}
-
-func GlibFloat(f float32) C.gfloat {
-	return C.gfloat(f)
-}
-
-func GoDouble(d C.gdouble) float64 {
-	return float64(d)
-}
-
-func GlibDouble(d float64) C.gdouble {
-	return C.gdouble(d)
-}
-
-func GoString(str *C.gchar) string {
-	return C.GoString(C.from_gchar(str))
-}
-
-func GlibString(str string) *C.gchar {
-	return C.to_gchar(C.CString(str))
-}
-
-func FreeString(str *C.gchar) {
-	C.g_free((C.gpointer)(str))
-}
-
-func GListToGo(glist *C.GList) *list.List {
-	result := list.New()
-	for glist != nil {
-		result.PushBack(glist.data)
-		glist = glist.next
-	}
-	return result
-}
-
-func PopulateFlags(data interface{}, bits C.gint, flags []C.gint) {
-	value := reflect.ValueOf(data).Elem()
-	for i := range flags {
-		value.Field(i).SetBool(GoBool(C.and(bits, flags[i])))
-	}
-}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// Reasons a symbol can be skipped for.
+const (
+	SkipUnsupported      = "unsupported type"
+	SkipUnintrospectable = "not introspectable"
+	SkipBlacklisted      = "blacklisted"
+	SkipDeprecated       = "deprecated"
+	SkipCollision        = "name collision"
+	SkipNotImplemented   = "not implemented"
+)
+
+// Symbol is something in a namespace that bindings can be generated for.
+// Names are qualified: "Gtk.Widget" for types, "Gtk.Widget.show" for
+// methods, "Gtk.Widget::destroy" for signals and "Gtk.Widget:visible" for
+// properties.
+type Symbol struct {
+	Kind   string `json:"kind"` // enum, flags, class, interface, constant, function, method, signal or property
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"` // why it was skipped, one of the Skip constants
+	Detail string `json:"detail,omitempty"`
+}
+
+// NamespaceCoverage lists what was and wasn't bound in one namespace.
+type NamespaceCoverage struct {
+	Namespace string   `json:"namespace"`
+	Percent   float64  `json:"percent"`
+	Bound     []Symbol `json:"bound"`
+	Skipped   []Symbol `json:"skipped"`
+}
+
+// Coverage is the report of what a run of the generator bound, one entry
+// per namespace in the order they were generated in.
+type Coverage struct {
+	Namespaces []*NamespaceCoverage `json:"namespaces"`
+}
+
+// since is the detail given for deprecated symbols.
+func since(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "since " + version
+}
+
+// coverage collects what a single render job bound and skipped. A nil
+// *coverage ignores everything.
+type coverage struct {
+	bound   []Symbol
+	skipped []Symbol
+}
+
+func (c *coverage) bind(kind, name string) {
+	if c == nil {
+		return
+	}
+	c.bound = append(c.bound, Symbol{Kind: kind, Name: name})
+}
+
+func (c *coverage) skip(kind, name, reason, detail string) {
+	if c == nil {
+		return
+	}
+	c.skipped = append(c.skipped, Symbol{Kind: kind, Name: name, Reason: reason, Detail: detail})
+}
+
+// skipClass records a class that wasn't generated, along with everything
+// in it.
+func (c *coverage) skipClass(obj *model.Class, reason, detail string) {
+	name := model.QualifiedName(obj.Namespace, obj.Name)
+	c.skip("class", name, reason, detail)
+	for _, method := range obj.Methods {
+		c.skip("method", name+"."+method.Name, reason, detail)
+	}
+	c.skipMembers(name, obj.Properties, obj.Signals, reason, detail)
+}
+
+// skipMembers records the properties and signals of a type, neither of
+// which have bindings generated for them yet.
+func (c *coverage) skipMembers(owner string, properties []*model.Property, signals []*model.Callable, reason, detail string) {
+	for _, prop := range properties {
+		c.skip("property", owner+":"+prop.Name, reason, detail)
+	}
+	for _, signal := range signals {
+		c.skip("signal", owner+"::"+signal.Name, reason, detail)
+	}
+}
+
+// skipUngenerated records the kinds of symbol in ns that the generator
+// doesn't handle at all yet.
+func (c *coverage) skipUngenerated(ns *model.Namespace) {
+	for _, iface := range ns.Interfaces {
+		name := model.QualifiedName(ns.Name, iface.Name)
+		c.skip("interface", name, SkipNotImplemented, "")
+		for _, method := range iface.Methods {
+			c.skip("method", name+"."+method.Name, SkipNotImplemented, "")
+		}
+		c.skipMembers(name, iface.Properties, iface.Signals, SkipNotImplemented, "")
+	}
+	for _, constant := range ns.Constants {
+		c.skip("constant", model.QualifiedName(ns.Name, constant.Name), SkipNotImplemented, "")
+	}
+}
+
+// add sorts what was collected by name and adds it to the report as the
+// coverage of namespace.
+func (report *Coverage) add(namespace string, c *coverage) {
+	// empty rather than null in JSON
+	bound := append([]Symbol{}, c.bound...)
+	skipped := append([]Symbol{}, c.skipped...)
+	sortSymbols(bound)
+	sortSymbols(skipped)
+	percent := 100.0
+	if total := len(bound) + len(skipped); total > 0 {
+		percent = 100 * float64(len(bound)) / float64(total)
+	}
+	report.Namespaces = append(report.Namespaces, &NamespaceCoverage{
+		Namespace: namespace,
+		Percent:   percent,
+		Bound:     bound,
+		Skipped:   skipped,
+	})
+}
+
+func sortSymbols(symbols []Symbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].Name != symbols[j].Name {
+			return symbols[i].Name < symbols[j].Name
+		}
+		return symbols[i].Kind < symbols[j].Kind
+	})
+}
+
+// Summary returns a line per namespace saying how much of it was bound.
+func (report *Coverage) Summary() string {
+	var b strings.Builder
+	for _, ns := range report.Namespaces {
+		fmt.Fprintf(&b, "%s: %d of %d symbols bound (%.1f%%)\n", ns.Namespace, len(ns.Bound), len(ns.Bound)+len(ns.Skipped), ns.Percent)
+	}
+	return b.String()
+}
+
+// WriteText writes the summary followed by every skipped symbol.
+func (report *Coverage) WriteText(w io.Writer) error {
+	if _, err := io.WriteString(w, report.Summary()); err != nil {
+		return err
+	}
+	for _, ns := range report.Namespaces {
+		if len(ns.Skipped) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s skipped:\n", ns.Namespace)
+		for _, sym := range ns.Skipped {
+			line := fmt.Sprintf("  %-8s %s: %s", sym.Kind, sym.Name, sym.Reason)
+			if sym.Detail != "" {
+				line += " (" + sym.Detail + ")"
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the whole report, including what was bound.
+func (report *Coverage) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteFile writes the report to filename, as JSON if it ends in .json and
+// as text otherwise.
+func (report *Coverage) WriteFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(filename, ".json") {
+		err = report.WriteJSON(f)
+	} else {
+		err = report.WriteText(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadCoverage reads a report written by WriteJSON.
+func ReadCoverage(filename string) (*Coverage, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	report := &Coverage{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return report, nil
+}
+
+// Regressions returns the symbols that were bound in baseline but aren't
+// any more, with the reason they were skipped this time. Everything bound
+// in a namespace that isn't in report at all is lost too, as "gone".
+func (report *Coverage) Regressions(baseline *Coverage) []Symbol {
+	var lost []Symbol
+	for _, old := range baseline.Namespaces {
+		var ns *NamespaceCoverage
+		for _, current := range report.Namespaces {
+			if current.Namespace == old.Namespace {
+				ns = current
+				break
+			}
+		}
+		if ns == nil {
+			for _, sym := range old.Bound {
+				sym.Reason, sym.Detail = "gone", old.Namespace+" wasn't generated"
+				lost = append(lost, sym)
+			}
+			continue
+		}
+
+		bound := make(map[Symbol]bool)
+		for _, sym := range ns.Bound {
+			bound[sym] = true
+		}
+		skipped := make(map[Symbol]Symbol)
+		for _, sym := range ns.Skipped {
+			skipped[Symbol{Kind: sym.Kind, Name: sym.Name}] = sym
+		}
+		for _, sym := range old.Bound {
+			if bound[sym] {
+				continue
+			}
+			if why, ok := skipped[sym]; ok {
+				sym = why
+			} else {
+				sym.Reason = "gone"
+			}
+			lost = append(lost, sym)
+		}
+	}
+	return lost
+}
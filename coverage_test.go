@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegressions(t *testing.T) {
+	baseline := &Coverage{Namespaces: []*NamespaceCoverage{
+		{
+			Namespace: "GoTest",
+			Bound: []Symbol{
+				{Kind: "method", Name: "GoTest.Thing.add"},
+				{Kind: "method", Name: "GoTest.Thing.scale"},
+				{Kind: "method", Name: "GoTest.Thing.set_uri"},
+			},
+		},
+		{
+			Namespace: "GObject",
+			Bound:     []Symbol{{Kind: "class", Name: "GObject.Object"}},
+		},
+	}}
+	report := &Coverage{Namespaces: []*NamespaceCoverage{
+		{
+			Namespace: "GoTest",
+			Bound:     []Symbol{{Kind: "method", Name: "GoTest.Thing.add"}},
+			Skipped:   []Symbol{{Kind: "method", Name: "GoTest.Thing.scale", Reason: SkipBlacklisted, Detail: "go_test_thing_scale"}},
+		},
+		{Namespace: "Gtk"},
+	}}
+	want := []Symbol{
+		{Kind: "method", Name: "GoTest.Thing.scale", Reason: SkipBlacklisted, Detail: "go_test_thing_scale"},
+		{Kind: "method", Name: "GoTest.Thing.set_uri", Reason: "gone"},
+		{Kind: "class", Name: "GObject.Object", Reason: "gone", Detail: "GObject wasn't generated"},
+	}
+	if got := report.Regressions(baseline); !reflect.DeepEqual(got, want) {
+		t.Errorf("Regressions() = %+v, want %+v", got, want)
+	}
+}
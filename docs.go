@@ -0,0 +1,371 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// DocWriter turns the documentation in .gir files into Go doc comments.
+// The gtk-doc markup in it is translated as it goes: #GtkWidget and
+// gtk_widget_show() become doc links to the generated type and method,
+// %TRUE, %FALSE and %NULL become true, false and nil, and @param becomes
+// the Go name of the parameter. The newer gi-docgen links, such as
+// [class@Gtk.Widget], are understood too. Only what's generated is linked
+// to; anything else is left as its C name.
+type DocWriter struct {
+	module    string
+	names     *Names
+	types     map[string]docTarget // by C type and by qualified name
+	symbols   map[string]docTarget // by C symbol and by qualified name
+	constants map[string]docTarget // enum members by C identifier
+	cnames    map[string]string    // of what isn't generated, by qualified name
+}
+
+// docTarget is something generated that docs can link to.
+type docTarget struct {
+	namespace string
+	name      string // the Go name, i.e. "Widget" or "Widget.Show"
+}
+
+// NewDocWriter returns a DocWriter for generating the namespaces that
+// blacklists has the blacklist of, which can link to what's generated for
+// any of them. Deprecated things are linked to if deprecated is set, since
+// they're only generated then.
+func NewDocWriter(namespaces model.Namespaces, blacklists map[string]map[string]bool, deprecated bool, module string, names *Names) *DocWriter {
+	docs := &DocWriter{
+		module:    module,
+		names:     names,
+		types:     make(map[string]docTarget),
+		symbols:   make(map[string]docTarget),
+		constants: make(map[string]docTarget),
+		cnames:    make(map[string]string),
+	}
+	for name, blacklist := range blacklists {
+		ns := namespaces[name]
+		for _, enum := range ns.Enums {
+			if enum.Deprecated && !deprecated {
+				docs.cnames[model.QualifiedName(ns.Name, enum.Name)] = enum.CType
+				continue
+			}
+			target := docTarget{ns.Name, enum.Name}
+			docs.types[enum.CType] = target
+			docs.types[model.QualifiedName(ns.Name, enum.Name)] = target
+			for _, value := range enum.Values {
+				if value.CIdentifier != "" {
+					docs.constants[value.CIdentifier] = docTarget{ns.Name, names.Value(ns.Name, enum, value)}
+				}
+			}
+		}
+		for _, obj := range ns.Classes {
+			qualified := model.QualifiedName(ns.Name, obj.Name)
+			skipped := obj.Deprecated && !deprecated
+			if skipped {
+				docs.cnames[qualified] = obj.CType
+			} else {
+				target := docTarget{ns.Name, obj.Name}
+				docs.types[obj.CType] = target
+				docs.types[qualified] = target
+			}
+			for _, method := range obj.Methods {
+				if skipped || !generated(method, blacklist, deprecated) {
+					docs.cnames[qualified+"."+method.Name] = method.Symbol + "()"
+					continue
+				}
+				target := docTarget{ns.Name, obj.Name + "." + names.Method(obj, method)}
+				docs.symbols[method.Symbol] = target
+				docs.symbols[qualified+"."+method.Name] = target
+			}
+		}
+		for _, fn := range ns.Functions {
+			qualified := model.QualifiedName(ns.Name, fn.Name)
+			if !generated(fn, blacklist, deprecated) {
+				docs.cnames[qualified] = fn.Symbol + "()"
+				continue
+			}
+			target := docTarget{ns.Name, names.Function(ns.Name, fn)}
+			docs.symbols[fn.Symbol] = target
+			docs.symbols[qualified] = target
+		}
+	}
+	return docs
+}
+
+// Type returns the doc comment for a type generated into namespace as
+// name, which says what C type it wraps before the description. Lists of
+// its properties and signals come after, since those don't have anything
+// generated for them to be documented on, and then deprecation, if it's
+// deprecated.
+func (docs *DocWriter) Type(namespace, name, ctype, doc, deprecation string, properties []*model.Property, signals []*model.Callable) string {
+	if docs == nil {
+		return ""
+	}
+	text := paragraphs(name+" wraps "+ctype+".", docs.convert(namespace, doc))
+	var props, sigs []string
+	for _, prop := range properties {
+		props = append(props, "  - "+prop.Name+deprecatedItem(prop.Deprecated)+docs.item(namespace, prop.Doc))
+	}
+	for _, signal := range signals {
+		sigs = append(sigs, "  - "+signal.Name+deprecatedItem(signal.Deprecated)+docs.item(namespace, signal.Doc))
+	}
+	if len(props) > 0 {
+		text = paragraphs(text, "# Properties", strings.Join(props, "\n"))
+	}
+	if len(sigs) > 0 {
+		text = paragraphs(text, "# Signals", strings.Join(sigs, "\n"))
+	}
+	return comment(paragraphs(text, deprecation), "")
+}
+
+// Function returns the doc comment for a function or method generated into
+// namespace as name, which says what C function it wraps before the
+// description, and lists its parameters and what it returns after.
+// Parameters that are skipped aren't listed, and are referred to by their
+// C names, since the Go function doesn't have them.
+func (docs *DocWriter) Function(namespace, name string, fn *model.Callable) string {
+	if docs == nil {
+		return ""
+	}
+	names := docs.names.Params(fn)
+	text := paragraphs(name+" wraps "+fn.Symbol+"().", docs.convert(namespace, paramRefs(fn, names, fn.Doc)))
+	var params []string
+	for i, param := range fn.Params {
+		if param.Doc != "" && !param.Skip {
+			params = append(params, "  - "+names[i]+docs.item(namespace, paramRefs(fn, names, param.Doc)))
+		}
+	}
+	if len(params) > 0 {
+		text = paragraphs(text, strings.Join(params, "\n"))
+	}
+	if ret := docs.convert(namespace, paramRefs(fn, names, fn.ReturnDoc)); ret != "" {
+		text = paragraphs(text, "Returns "+strings.TrimSuffix(ret, ".")+".")
+	}
+	text = paragraphs(text, docs.Deprecated(namespace, fn.Deprecated, fn.DeprecatedVersion, fn.DeprecatedDoc))
+	return comment(text, "")
+}
+
+// Deprecated returns the paragraph that marks something as deprecated for
+// godoc and staticcheck, saying since when and what to use instead if
+// that's known, or "" if it isn't deprecated.
+func (docs *DocWriter) Deprecated(namespace string, deprecated bool, version, doc string) string {
+	if docs == nil || !deprecated {
+		return ""
+	}
+	text := "Deprecated:"
+	if version != "" {
+		text += " Since " + version + "."
+	}
+	if doc = docs.convert(namespace, doc); doc != "" {
+		text += " " + doc
+	} else if version == "" {
+		text += " Don't use it in new code."
+	}
+	return text
+}
+
+// paramRefs replaces references in doc to the parameters of fn with their
+// Go names, as given by Names.Params, since those are the ones in the
+// signature. Parameters that are skipped are left as their C names.
+func paramRefs(fn *model.Callable, names []string, doc string) string {
+	for i, param := range fn.Params {
+		ref := regexp.MustCompile(`@` + regexp.QuoteMeta(param.Name) + `\b`)
+		name := names[i]
+		if param.Skip {
+			name = param.Name
+		}
+		doc = ref.ReplaceAllLiteralString(doc, name)
+	}
+	return doc
+}
+
+func deprecatedItem(deprecated bool) string {
+	if deprecated {
+		return " (deprecated)"
+	}
+	return ""
+}
+
+// Value returns the doc comment for a member of an enum, indented to go
+// inside its const block.
+func (docs *DocWriter) Value(namespace, doc string) string {
+	if docs == nil {
+		return ""
+	}
+	return comment(docs.convert(namespace, doc), "\t")
+}
+
+// item formats doc to follow a name in a list, all on one line.
+func (docs *DocWriter) item(namespace, doc string) string {
+	text := strings.Join(strings.Fields(docs.convert(namespace, doc)), " ")
+	if text == "" {
+		return ""
+	}
+	return ": " + text
+}
+
+// paragraphs joins the non-empty parts with blank lines.
+func paragraphs(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
+
+// comment turns text into // lines, each starting with indent. Empty text
+// gives an empty comment, so that templates can use it unconditionally.
+func comment(text, indent string) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent + "//")
+		if line != "" {
+			if line[0] != '\t' {
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// convert translates the markup in doc. Code blocks, marked with |[ ]| or
+// ```, are indented instead so that godoc shows them as code, and are
+// otherwise left alone.
+func (docs *DocWriter) convert(namespace, doc string) string {
+	var lines []string
+	code := false
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !code && (strings.HasPrefix(trimmed, "|[") || strings.HasPrefix(trimmed, "```")):
+			code = true
+			continue
+		case code && (strings.HasPrefix(trimmed, "]|") || strings.HasPrefix(trimmed, "```")):
+			code = false
+			continue
+		case code:
+			if line != "" {
+				line = "\t" + line
+			}
+		default:
+			line = docs.markup(namespace, line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// docMarkup matches, in order: gi-docgen links, #Type with an optional
+// :property or ::signal, function(), %CONSTANT and @param.
+var docMarkup = regexp.MustCompile(`\[(\w+)@([\w.:-]+)\]|#([A-Za-z_]\w*)(?:(::?)([\w-]+))?|\b([A-Za-z_]\w*)\(\)|%([A-Za-z_]\w*)|@([A-Za-z_]\w*)`)
+
+func (docs *DocWriter) markup(namespace, line string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range docMarkup.FindAllStringSubmatchIndex(line, -1) {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return line[m[2*i]:m[2*i+1]]
+		}
+		start := m[0]
+		if start > 0 && isWordByte(line[start-1]) && line[start] != '[' {
+			// i.e. an email address, or issue#123
+			continue
+		}
+		b.WriteString(line[last:start])
+		last = m[1]
+
+		switch {
+		case group(1) != "":
+			b.WriteString(docs.docgenLink(namespace, group(1), group(2)))
+		case group(3) != "":
+			b.WriteString(docs.typeLink(namespace, group(3)))
+			b.WriteString(group(4) + group(5))
+		case group(6) != "":
+			if target, ok := docs.symbols[group(6)]; ok {
+				b.WriteString(docs.link(namespace, target))
+			} else {
+				b.WriteString(group(0))
+			}
+		case group(7) != "":
+			b.WriteString(docs.constant(namespace, group(7)))
+		case group(8) != "":
+			b.WriteString(docs.names.Param(group(8)))
+		}
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// docgenLink translates a gi-docgen link like [method@Gtk.Widget.show],
+// into the C name if what it links to isn't generated.
+func (docs *DocWriter) docgenLink(namespace, kind, name string) string {
+	switch kind {
+	case "class", "enum", "flags", "iface", "struct", "type":
+		if target, ok := docs.types[name]; ok {
+			return docs.link(namespace, target)
+		}
+	case "method", "ctor", "func":
+		if target, ok := docs.symbols[name]; ok {
+			return docs.link(namespace, target)
+		}
+	case "id":
+		if target, ok := docs.symbols[name]; ok {
+			return docs.link(namespace, target)
+		}
+		if target, ok := docs.constants[name]; ok {
+			return docs.link(namespace, target)
+		}
+	}
+	if cname, ok := docs.cnames[name]; ok {
+		return cname
+	}
+	return name
+}
+
+func (docs *DocWriter) typeLink(namespace, ctype string) string {
+	if target, ok := docs.types[ctype]; ok {
+		return docs.link(namespace, target)
+	}
+	return ctype
+}
+
+func (docs *DocWriter) constant(namespace, name string) string {
+	switch name {
+	case "TRUE":
+		return "true"
+	case "FALSE":
+		return "false"
+	case "NULL":
+		return "nil"
+	}
+	if target, ok := docs.constants[name]; ok {
+		return docs.link(namespace, target)
+	}
+	return name
+}
+
+// link returns a doc link to target from the package of namespace. Links
+// into other packages use the full import path, since the package might
+// not import it.
+func (docs *DocWriter) link(namespace string, target docTarget) string {
+	if target.namespace == namespace {
+		return "[" + target.name + "]"
+	}
+	return "[" + path.Join(docs.module, strings.ToLower(target.namespace)) + "." + target.name + "]"
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
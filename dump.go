@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// dump implements the dump subcommand, which writes out the model the
+// generator would work from as JSON, for seeing what it sees, diffing
+// library versions and as test input.
+func dump(args []string) {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	flags.StringVar(girPath, "gir", "", "read .gir files from this list of directories instead of installed typelibs")
+	flags.StringVar(typelibPath, "typelibdir", "", "list of directories to search for typelibs before the default ones")
+	flags.StringVar(libraryPath, "libdir", "", "list of directories to search for the libraries typelibs refer to")
+	dir := flags.String("o", "", "directory to write <namespace>-<version>.json files to (default: standard output)")
+	deps := flags.Bool("deps", false, "also dump every namespace it depends on; needs -o")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-gi dump [flags] <namespace>[-<version>] | <file.gir> | <file.typelib>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*deps && *dir == "") {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	prependSearchPaths()
+	namespaces, namespace, err := loadNamespaces(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if *dir == "" {
+		if err := model.WriteJSON(os.Stdout, namespaces[namespace]); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	names := []string{namespace}
+	if *deps {
+		names = namespaces.DependencyOrder(namespace)
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	for _, name := range names {
+		ns := namespaces[name]
+		filename := filepath.Join(*dir, ns.Name + "-" + ns.Version + ".json")
+		if err := writeJSONFile(filename, ns); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "[*] Model written to " + filename)
+	}
+}
+
+func writeJSONFile(filename string, ns *model.Namespace) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := model.WriteJSON(f, ns); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// Generator renders namespaces into Go source. Every enum, class and
+// function is rendered into a buffer of its own by a pool of workers, and
+// the buffers are then put back together sorted by name, so the output
+// doesn't depend on the number of workers or the order the loader found
+// things in.
+type Generator struct {
+	Namespaces  model.Namespaces
+	Snippets    *template.Template
+	Templates   fs.FS // per-namespace package headers
+	Blacklist   fs.FS // per-namespace symbol blacklists
+	Overrides   fs.FS // per-namespace overrides, see Override
+	Module      string
+	Workers     int
+	Deprecated  bool     // generate deprecated symbols too
+	Initialisms []string // words to spell a particular way in Go names, on top of the usual ones
+
+	names      *Names
+	docs       *DocWriter
+	blacklists map[string]map[string] bool // by namespace
+	gates      *VersionGates
+	overrides  Overrides
+}
+
+// HeaderDefinition is passed to the "header" snippet for namespaces that
+// don't have a template of their own.
+type HeaderDefinition struct {
+	Package   string
+	Namespace string
+	Packages  []string // pkg-config packages
+	Libraries []string // libraries to link against when there aren't any packages
+	CIncludes []string
+}
+
+// linkName turns the file name of a shared library into the name the
+// linker expects, i.e. "libgtk-3.so.0" into "gtk-3".
+func linkName(lib string) string {
+	name := strings.TrimPrefix(filepath.Base(lib), "lib")
+	if i := strings.Index(name, ".so"); i >= 0 {
+		name = name[:i]
+	} else if i := strings.Index(name, ".dylib"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// renderJob is a single enum, class or function waiting to be rendered.
+type renderJob struct {
+	namespace string
+	enum      *model.Enumeration
+	class     *model.Class
+	function  *model.Callable
+	blacklist map[string] bool
+
+	code *Code
+	cov  coverage
+}
+
+func (job *renderJob) render(g *Generator) {
+	job.code = NewCode(g.gates)
+	if job.enum != nil {
+		ProcessEnum(job.enum, job.namespace, job.code, g.Snippets, &job.blacklist, g.Deprecated, g.names, g.docs, &job.cov)
+		return
+	}
+	if job.function != nil {
+		ProcessFunction(job.function, job.namespace, job.code, g.Snippets, &job.blacklist, g.overrides, g.Deprecated, g.names, g.docs, &job.cov)
+		return
+	}
+	// used to prevent duplicate methods
+	exists := make(map[string] bool)
+	ProcessObject(job.class, g.Namespaces, job.code, g.Snippets, &exists, g.blacklists, g.overrides, g.Deprecated, g.names, g.docs, &job.cov)
+}
+
+// Generate renders each of the named namespaces, returning the contents of
+// their files by path relative to the output directory, i.e.
+// "gtk/gtk.go", along with a report of what they cover. Code that needs a
+// recent version of a library goes into a file of its own, named after
+// its build tag. The overrides of each namespace are applied to its model
+// first.
+func (g *Generator) Generate(names []string) (map[string] []byte, *Coverage, error) {
+	g.gates = NewVersionGates(g.Namespaces)
+	g.overrides = make(Overrides)
+	headers := make(map[string] *bytes.Buffer)
+	blacklists := make(map[string]map[string] bool)
+	var jobs []*renderJob
+	for _, namespace := range names {
+		header, err := g.header(namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		headers[namespace] = header
+		blacklist, err := g.blacklist(namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		blacklists[namespace] = blacklist
+		overrides, err := g.namespaceOverrides(namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides.Apply(g.Namespaces[namespace])
+		for symbol, override := range overrides {
+			g.overrides[symbol] = override
+		}
+
+		// types are rendered by name rather than in the order the loader
+		// found them in, so upgrading a library only changes what changed
+		ns := g.Namespaces[namespace]
+		for _, enum := range ns.SortedEnums() {
+			jobs = append(jobs, &renderJob{namespace:namespace, enum:enum, blacklist:blacklist})
+		}
+		for _, obj := range ns.SortedClasses() {
+			jobs = append(jobs, &renderJob{namespace:namespace, class:obj, blacklist:blacklist})
+		}
+		for _, fn := range ns.SortedFunctions() {
+			jobs = append(jobs, &renderJob{namespace:namespace, function:fn, blacklist:blacklist})
+		}
+	}
+	// inherited methods are checked against the blacklist of the namespace
+	// they come from, which may not be one of those being generated
+	for namespace := range g.Namespaces {
+		if _, ok := blacklists[namespace]; ok {
+			continue
+		}
+		blacklist, err := g.blacklist(namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		blacklists[namespace] = blacklist
+	}
+	g.blacklists = blacklists
+
+	// overrides can rename things, and change what their docs list
+	g.names = NewNames(g.Namespaces, g.Initialisms, g.overrides)
+	g.docs = NewDocWriter(g.Namespaces, blacklists, g.Deprecated, g.Module, g.names)
+
+	queue := make(chan *renderJob)
+	var wg sync.WaitGroup
+	workers := g.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job.render(g)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	files := make(map[string] []byte)
+	report := &Coverage{}
+	for _, namespace := range names {
+		code := NewCode(g.gates)
+		var cov coverage
+		for _, job := range jobs {
+			if job.namespace != namespace {
+				continue
+			}
+			for tag, part := range job.code.Files {
+				file := code.Tagged(tag)
+				part.WriteTo(file)
+				for pkg := range part.Imports {
+					file.Imports[pkg] = true
+				}
+			}
+			cov.bound = append(cov.bound, job.cov.bound...)
+			cov.skipped = append(cov.skipped, job.cov.skipped...)
+		}
+		cov.skipUngenerated(g.Namespaces[namespace])
+		report.add(namespace, &cov)
+
+		pkg := strings.ToLower(namespace)
+		header := headers[namespace]
+		files[path.Join(pkg, pkg + ".go")] = g.file(header.Bytes(), code.Tagged(""))
+		for tag, file := range code.Files {
+			if tag == "" {
+				continue
+			}
+			var tagged bytes.Buffer
+			fmt.Fprintf(&tagged, "//go:build %s\n\n", g.gates.Constraint(tag))
+			tagged.Write(header.Bytes())
+			files[path.Join(pkg, tag + ".go")] = g.file(tagged.Bytes(), file)
+		}
+	}
+	return files, report, nil
+}
+
+// stdPackages are the standard packages generated code can use besides
+// unsafe, which every header imports.
+var stdPackages = []string{"errors"}
+
+// file puts together a header and the code that goes after it, importing
+// the standard packages and the packages of the other namespaces the code
+// refers to.
+func (g *Generator) file(header []byte, code *CodeFile) []byte {
+	out := bytes.NewBuffer(trimHeader(header, code.Bytes()))
+	for _, pkg := range stdPackages {
+		if bytes.Contains(code.Bytes(), []byte(pkg + ".")) {
+			fmt.Fprintf(out, "import \"%s\"\n", pkg)
+		}
+	}
+	packages := make([]string, 0, len(code.Imports))
+	for pkg := range code.Imports {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	for _, pkg := range packages {
+		fmt.Fprintf(out, "import \"%s\"\n", path.Join(g.Module, pkg))
+	}
+	if len(packages) > 0 {
+		out.WriteString("\n")
+	}
+	out.Write(code.Bytes())
+	return out.Bytes()
+}
+
+// trimHeader adapts the header of a namespace for one of its files, which
+// doesn't need the unsafe package unless its code uses it.
+func trimHeader(header, code []byte) []byte {
+	if bytes.Contains(code, []byte("unsafe.")) {
+		return header
+	}
+	return bytes.Replace(header, []byte("import \"unsafe\"\n"), nil, 1)
+}
+
+func (g *Generator) header(namespace string) (*bytes.Buffer, error) {
+	ns := strings.ToLower(namespace)
+	var header bytes.Buffer
+	if data, err := fs.ReadFile(g.Templates, ns + ".go.tmpl"); err == nil {
+		header.Write(data)
+		return &header, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr, "[!] No template for " + namespace + ", using a generic header")
+	def := HeaderDefinition{
+		Package:   ns,
+		Namespace: namespace,
+		Packages:  g.Namespaces[namespace].Packages,
+		CIncludes: g.Namespaces[namespace].CIncludes,
+	}
+	for _, lib := range g.Namespaces[namespace].SharedLibraries {
+		def.Libraries = append(def.Libraries, linkName(lib))
+	}
+	if err := g.Snippets.ExecuteTemplate(&header, "header", def); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+func (g *Generator) blacklist(namespace string) (map[string] bool, error) {
+	blacklist := make(map[string] bool)
+	data, err := fs.ReadFile(g.Blacklist, strings.ToLower(namespace))
+	if errors.Is(err, fs.ErrNotExist) {
+		return blacklist, nil
+	} else if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines {
+		if len(line) == 0 || line[0:1] == "#" {
+			continue
+		}
+		blacklist[line] = true
+	}
+	return blacklist, nil
+}
+
+// WriteFile writes a file returned by Generate to where it goes under
+// outputDir, returning the path it was written to.
+func WriteFile(outputDir, name string, data []byte) (string, error) {
+	filename := filepath.Join(outputDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return "", err
+	}
+	return filename, os.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// newGenerator returns a generator for namespaces that uses the built in
+// snippets, templates, blacklists and overrides.
+func newGenerator(tb testing.TB, namespaces model.Namespaces) *Generator {
+	tb.Helper()
+	tmpl, err := template.New("go-gi").ParseFS(Assets("", "snippets"), "*")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return &Generator{
+		Namespaces: namespaces,
+		Snippets:   tmpl,
+		Templates:  Assets("", "templates"),
+		Blacklist:  Assets("", "blacklist"),
+		Overrides:  Assets("", "overrides"),
+		Module:     "gi",
+		Workers:    1,
+	}
+}
+
+// benchmarkNamespaces are what BenchmarkGenerate generates, whichever is
+// found first: GoTest is too small to say much about real libraries.
+var benchmarkNamespaces = []string{"Gtk", "Regress"}
+
+// BenchmarkGenerate generates a real namespace and everything it depends
+// on, read from .gir files so that it doesn't need any typelibs, with one
+// worker and with as many as can run at once. The files are looked for in
+// the directories given with -gir and then where distributions install
+// them, and the benchmark is skipped without them.
+func BenchmarkGenerate(b *testing.B) {
+	loader := model.GIRLoader{Path: append(filepath.SplitList(*girPath), model.DefaultGIRPath...)}
+	var namespaces model.Namespaces
+	var order []string
+	for _, namespace := range benchmarkNamespaces {
+		if loader.Find(namespace, "") == "" {
+			continue
+		}
+		var err error
+		namespaces, err = model.LoadAll(loader, namespace, "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		order = namespaces.DependencyOrder(namespace)
+		break
+	}
+	if namespaces == nil {
+		b.Skip("none of " + strings.Join(benchmarkNamespaces, ", ") + " has a .gir file installed; use -gir")
+	}
+
+	run := func(workers int) func(*testing.B) {
+		return func(b *testing.B) {
+			g := newGenerator(b, namespaces)
+			g.Workers = workers
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := g.Generate(order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.Run("j=1", run(1))
+	b.Run("j=GOMAXPROCS", run(runtime.GOMAXPROCS(0)))
+}
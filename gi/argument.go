@@ -0,0 +1,271 @@
+package gi
+
+/*
+#cgo pkg-config: glib-2.0 gobject-introspection-1.0
+#include <stdlib.h>
+#include <glib.h>
+#include <girepository.h>
+
+// GIArgument is a union, which cgo only sees as an array of bytes
+static gboolean arg_boolean(GIArgument *arg) { return arg->v_boolean; }
+static gint8 arg_int8(GIArgument *arg) { return arg->v_int8; }
+static guint8 arg_uint8(GIArgument *arg) { return arg->v_uint8; }
+static gint16 arg_int16(GIArgument *arg) { return arg->v_int16; }
+static guint16 arg_uint16(GIArgument *arg) { return arg->v_uint16; }
+static gint32 arg_int32(GIArgument *arg) { return arg->v_int32; }
+static guint32 arg_uint32(GIArgument *arg) { return arg->v_uint32; }
+static gint64 arg_int64(GIArgument *arg) { return arg->v_int64; }
+static guint64 arg_uint64(GIArgument *arg) { return arg->v_uint64; }
+static gfloat arg_float(GIArgument *arg) { return arg->v_float; }
+static gdouble arg_double(GIArgument *arg) { return arg->v_double; }
+static gsize arg_size(GIArgument *arg) { return arg->v_size; }
+static gchar *arg_string(GIArgument *arg) { return arg->v_string; }
+static gpointer arg_pointer(GIArgument *arg) { return arg->v_pointer; }
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// goFromArgument converts the value held by arg to the Go type matching
+// tag. Anything that isn't a basic type comes back as an unsafe.Pointer.
+func goFromArgument(arg *C.GIArgument, tag TypeTag) interface{} {
+	switch tag {
+	case VoidTag:
+		return unsafe.Pointer(C.arg_pointer(arg))
+	case BooleanTag:
+		return goBool(C.arg_boolean(arg))
+	case Int8Tag:
+		return int8(C.arg_int8(arg))
+	case Uint8Tag:
+		return uint8(C.arg_uint8(arg))
+	case Int16Tag:
+		return int16(C.arg_int16(arg))
+	case Uint16Tag:
+		return uint16(C.arg_uint16(arg))
+	case Int32Tag:
+		return int32(C.arg_int32(arg))
+	case Uint32Tag, UnicharTag:
+		return uint32(C.arg_uint32(arg))
+	case Int64Tag:
+		return int64(C.arg_int64(arg))
+	case Uint64Tag:
+		return uint64(C.arg_uint64(arg))
+	case FloatTag:
+		return float32(C.arg_float(arg))
+	case DoubleTag:
+		return float64(C.arg_double(arg))
+	case GTypeTag:
+		return GType(C.arg_size(arg))
+	case Utf8Tag, FilenameTag:
+		str := C.arg_string(arg)
+		if str == nil {
+			return ""
+		}
+		return goString(str)
+	}
+	return unsafe.Pointer(C.arg_pointer(arg))
+}
+
+// argumentToGo is goFromArgument for a value described by typ, which also
+// knows about enums and flags. Strings that the caller owns are freed once
+// they've been copied.
+func argumentToGo(arg *C.GIArgument, typ *TypeInfo, transfer Transfer) interface{} {
+	tag := typ.GetTag()
+	if tag == InterfaceTag {
+		iface := typ.GetInterface()
+		defer iface.Free()
+		switch iface.Type {
+		case Enum:
+			return int32(C.arg_int32(arg))
+		case Flags:
+			return uint32(C.arg_uint32(arg))
+		}
+		return unsafe.Pointer(C.arg_pointer(arg))
+	}
+	value := goFromArgument(arg, tag)
+	if (tag == Utf8Tag || tag == FilenameTag) && transfer == Everything {
+		C.g_free(C.arg_pointer(arg))
+	}
+	return value
+}
+
+// callMemory is what the arguments of a call hold on to until it returns:
+// the C copies of strings, and the Go pointers stored in the argument
+// arrays, which are C memory and so can only hold pinned Go pointers.
+type callMemory struct {
+	owned  []unsafe.Pointer
+	pinner runtime.Pinner
+}
+
+// release frees the strings and unpins the pointers.
+func (mem *callMemory) release() {
+	for _, ptr := range mem.owned {
+		C.g_free((C.gpointer)(ptr))
+	}
+	mem.owned = nil
+	mem.pinner.Unpin()
+}
+
+// argumentFromGo stores value in arg as the type described by typ. Every
+// member of the union starts at its beginning, so values are written
+// through a pointer of the right type. Strings are copied into C memory,
+// which is added to mem unless the callee takes ownership of it.
+func argumentFromGo(arg *C.GIArgument, typ *TypeInfo, transfer Transfer, value interface{}, mem *callMemory) error {
+	p := unsafe.Pointer(arg)
+	tag := typ.GetTag()
+	if value == nil {
+		*(*unsafe.Pointer)(p) = nil
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch tag {
+	case BooleanTag:
+		b, ok := value.(bool)
+		if !ok {
+			return argumentTypeError(tag, value)
+		}
+		*(*C.gboolean)(p) = glibBool(b)
+	case Int8Tag, Uint8Tag, Int16Tag, Uint16Tag, Int32Tag, Uint32Tag, Int64Tag, Uint64Tag, UnicharTag:
+		i, ok := integerFromGo(v)
+		if !ok {
+			return argumentTypeError(tag, value)
+		}
+		storeInteger(p, tag, i)
+	case FloatTag, DoubleTag:
+		if v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64 {
+			return argumentTypeError(tag, value)
+		}
+		if tag == FloatTag {
+			*(*C.gfloat)(p) = C.gfloat(v.Float())
+		} else {
+			*(*C.gdouble)(p) = C.gdouble(v.Float())
+		}
+	case GTypeTag:
+		i, ok := integerFromGo(v)
+		if !ok {
+			return argumentTypeError(tag, value)
+		}
+		*(*C.gsize)(p) = C.gsize(i)
+	case Utf8Tag, FilenameTag:
+		str, ok := value.(string)
+		if !ok {
+			return argumentTypeError(tag, value)
+		}
+		_str := glibString(str)
+		if transfer == Everything {
+			// the callee will free it with g_free()
+			dup := C.g_strdup(_str)
+			freeString(_str)
+			_str = dup
+		} else {
+			mem.owned = append(mem.owned, unsafe.Pointer(_str))
+		}
+		*(**C.gchar)(p) = _str
+	case InterfaceTag:
+		iface := typ.GetInterface()
+		defer iface.Free()
+		if iface.Type == Enum || iface.Type == Flags {
+			i, ok := integerFromGo(v)
+			if !ok {
+				return argumentTypeError(tag, value)
+			}
+			*(*C.gint32)(p) = C.gint32(i)
+			return nil
+		}
+		fallthrough
+	default:
+		ptr, ok := pointerFromGo(value, &mem.pinner)
+		if !ok {
+			return argumentTypeError(tag, value)
+		}
+		*(*unsafe.Pointer)(p) = ptr
+	}
+	return nil
+}
+
+func argumentTypeError(tag TypeTag, value interface{}) error {
+	return fmt.Errorf("can't pass %T as %s", value, TypeTagToString(tag))
+}
+
+// integerFromGo returns the value of any Go integer, with unsigned ones
+// keeping their bits.
+func integerFromGo(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
+
+func storeInteger(p unsafe.Pointer, tag TypeTag, i int64) {
+	switch tag {
+	case Int8Tag:
+		*(*C.gint8)(p) = C.gint8(i)
+	case Uint8Tag:
+		*(*C.guint8)(p) = C.guint8(i)
+	case Int16Tag:
+		*(*C.gint16)(p) = C.gint16(i)
+	case Uint16Tag:
+		*(*C.guint16)(p) = C.guint16(i)
+	case Int32Tag:
+		*(*C.gint32)(p) = C.gint32(i)
+	case Uint32Tag, UnicharTag:
+		*(*C.guint32)(p) = C.guint32(i)
+	case Int64Tag:
+		*(*C.gint64)(p) = C.gint64(i)
+	case Uint64Tag:
+		*(*C.guint64)(p) = C.guint64(i)
+	}
+}
+
+// pointerFromGo returns the address held by an unsafe.Pointer or any Go
+// pointer. Generated bindings are pointers straight to their C objects, so
+// they can be passed as they are. Pointers to Go memory are pinned with
+// pinner until it's unpinned, since they're about to be stored in C memory;
+// pinning a C pointer does nothing.
+func pointerFromGo(value interface{}, pinner *runtime.Pinner) (unsafe.Pointer, bool) {
+	var ptr unsafe.Pointer
+	if p, ok := value.(unsafe.Pointer); ok {
+		ptr = p
+	} else if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		ptr = v.UnsafePointer()
+	} else {
+		return nil, false
+	}
+	if ptr != nil {
+		pinner.Pin(ptr)
+	}
+	return ptr, true
+}
+
+// newArguments allocates n arguments in C memory, since libgirepository
+// gets handed pointers into them. Free them with freeArguments.
+func newArguments(n int) []C.GIArgument {
+	if n == 0 {
+		return nil
+	}
+	ptr := (*C.GIArgument)(C.g_malloc0(C.gsize(n) * C.gsize(unsafe.Sizeof(C.GIArgument{}))))
+	return unsafe.Slice(ptr, n)
+}
+
+func freeArguments(args []C.GIArgument) {
+	if len(args) > 0 {
+		C.g_free(C.gpointer(&args[0]))
+	}
+}
+
+// firstArgument returns a pointer to the start of args, or nil if it's
+// empty.
+func firstArgument(args []C.GIArgument) *C.GIArgument {
+	if len(args) == 0 {
+		return nil
+	}
+	return &args[0]
+}
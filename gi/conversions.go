@@ -0,0 +1,221 @@
+package gi
+
+/*
+#cgo pkg-config: glib-2.0
+#include <glib.h>
+
+char *from_gchar(gchar *str) { return (char*)str; }
+gchar *to_gchar(char *str) { return (gchar*)str; }
+
+// bitflag support
+gboolean and(gint flags, gint position) {
+	return flags & position;
+}
+*/
+import "C"
+import (
+	"container/list"
+	"reflect"
+	"unsafe"
+)
+
+func goBool(b C.gboolean) bool {
+	if b == C.gboolean(0) {
+		return false
+	}
+	return true
+}
+
+func glibBool(b bool) C.gboolean {
+	if b {
+		return C.gboolean(1)
+	}
+	return C.gboolean(0)
+}
+
+func goChar(c C.gchar) int8 {
+	return int8(c)
+}
+
+func glibChar(i int8) C.gchar {
+	return C.gchar(i)
+}
+
+func goUChar(c C.guchar) uint {
+	return uint(c)
+}
+
+func glibUChar(i uint) C.guchar {
+	return C.guchar(i)
+}
+
+func goInt(i C.gint) int {
+	return int(i)
+}
+
+func glibInt(i int) C.gint {
+	return C.gint(i)
+}
+
+func goUInt(i C.guint) uint {
+	return uint(i)
+}
+
+func glibUInt(i uint) C.guint {
+	return C.guint(i)
+}
+
+func goInt8(i C.gint8) int8 {
+	return int8(i)
+}
+
+func glibInt8(i int8) C.gint8 {
+	return C.gint8(i)
+}
+
+func goUInt8(i C.guint8) uint8 {
+	return uint8(i)
+}
+
+func glibUInt8(i uint8) C.guint8 {
+	return C.guint8(i)
+}
+
+func goInt16(i C.gint16) int16 {
+	return int16(i)
+}
+
+func glibInt16(i int16) C.gint16 {
+	return C.gint16(i)
+}
+
+func goUInt16(i C.guint16) uint16 {
+	return uint16(i)
+}
+
+func glibUInt16(i uint16) C.guint16 {
+	return C.guint16(i)
+}
+
+func goInt32(i C.gint32) int32 {
+	return int32(i)
+}
+
+func glibInt32(i int32) C.gint32 {
+	return C.gint32(i)
+}
+
+func goUInt32(i C.guint32) uint32 {
+	return uint32(i)
+}
+
+func glibUInt32(i uint32) C.guint32 {
+	return C.guint32(i)
+}
+
+func goInt64(i C.gint64) int64 {
+	return int64(i)
+}
+
+func glibInt64(i int64) C.gint64 {
+	return C.gint64(i)
+}
+
+func goUInt64(i C.guint64) uint64 {
+	return uint64(i)
+}
+
+func glibUInt64(i uint64) C.guint64 {
+	return C.guint64(i)
+}
+
+func goShort(s C.gshort) int16 {
+	return int16(s)
+}
+
+func glibShort(s int16) C.gshort {
+	return C.gshort(s)
+}
+
+func goUShort(s C.gushort) uint16 {
+	return uint16(s)
+}
+
+func glibUShort(s uint16) C.gushort {
+	return C.gushort(s)
+}
+
+func goLong(l C.glong) int64 {
+	return int64(l)
+}
+
+func glibLong(l int64) C.glong {
+	return C.glong(l)
+}
+
+func goULong(l C.gulong) uint64 {
+	return uint64(l)
+}
+
+func glibULong(l uint64) C.gulong {
+	return C.gulong(l)
+}
+
+// TODO: gint8, gint16, etc.
+
+func goFloat(f C.gfloat) float32 {
+	return float32(f)
+}
+
+func glibFloat(f float32) C.gfloat {
+	return C.gfloat(f)
+}
+
+func goDouble(d C.gdouble) float64 {
+	return float64(d)
+}
+
+func glibDouble(d float64) C.gdouble {
+	return C.gdouble(d)
+}
+
+func goString(str *C.gchar) string {
+	return C.GoString(C.from_gchar(str))
+}
+
+func glibString(str string) *C.gchar {
+	return C.to_gchar(C.CString(str))
+}
+
+func freeString(str *C.gchar) {
+	C.g_free((C.gpointer)(str))
+}
+
+// GoStringArray converts a NULL-terminated string vector into a slice. The
+// vector itself is not freed.
+func goStringArray(strv **C.gchar) []string {
+	var result []string
+	if strv == nil {
+		return result
+	}
+	for p := strv; *p != nil; p = (**C.gchar)(unsafe.Add(unsafe.Pointer(p), unsafe.Sizeof(*p))) {
+		result = append(result, goString(*p))
+	}
+	return result
+}
+
+func gListToGo(glist *C.GList) *list.List {
+	result := list.New()
+	for glist != nil {
+		result.PushBack(glist.data)
+		glist = glist.next
+	}
+	return result
+}
+
+func populateFlags(data interface{}, bits C.gint, flags []C.gint) {
+	value := reflect.ValueOf(data).Elem()
+	for i := range flags {
+		value.Field(i).SetBool(goBool(C.and(bits, flags[i])))
+	}
+}
@@ -0,0 +1,1517 @@
+// Package gi wraps libgirepository, giving access to the GObject
+// introspection data of any installed library.
+package gi
+
+/*
+#cgo pkg-config: glib-2.0 gobject-2.0 gobject-introspection-1.0
+#include <stdlib.h>
+#include <glib.h>
+#include <glib-object.h>
+#include <girepository.h>
+
+gboolean check_version(gint major, gint minor) {
+	return GLIB_CHECK_VERSION(major, minor, 0);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+func init() {
+	// don't do this for GLib 2.36 and higher
+	if C.check_version(C.gint(2), C.gint(36)) == 0 {
+		C.g_type_init()
+	}
+}
+
+type GError struct {
+	Code int
+	Message string
+}
+
+func (self GError) Error() string {
+	return self.Message
+}
+
+func newGError(err *C.GError) GError {
+	defer C.g_error_free(err)
+	return GError{Code:goInt(err.code), Message:goString(err.message)}
+}
+
+
+
+type InfoType C.GIInfoType
+const (
+	Function InfoType = C.GI_INFO_TYPE_FUNCTION
+	Callback InfoType = C.GI_INFO_TYPE_CALLBACK
+	Struct InfoType = C.GI_INFO_TYPE_STRUCT
+	Boxed InfoType = C.GI_INFO_TYPE_BOXED
+	Enum InfoType = C.GI_INFO_TYPE_ENUM
+	Flags InfoType = C.GI_INFO_TYPE_FLAGS
+	Object InfoType = C.GI_INFO_TYPE_OBJECT
+	Interface InfoType = C.GI_INFO_TYPE_INTERFACE
+	Constant InfoType = C.GI_INFO_TYPE_CONSTANT
+	//ErrorDomain = C.GI_INFO_TYPE_ERRORDOMAIN
+	Union InfoType = C.GI_INFO_TYPE_UNION
+	Value InfoType = C.GI_INFO_TYPE_VALUE
+	Signal InfoType = C.GI_INFO_TYPE_SIGNAL
+	VFunc InfoType = C.GI_INFO_TYPE_VFUNC
+	Property InfoType = C.GI_INFO_TYPE_PROPERTY
+	Field InfoType = C.GI_INFO_TYPE_FIELD
+	Arg InfoType = C.GI_INFO_TYPE_ARG
+	Type InfoType = C.GI_INFO_TYPE_TYPE
+	Unresolved InfoType = C.GI_INFO_TYPE_UNRESOLVED
+)
+
+func InfoTypeToString(typ InfoType) string {
+	return goString(C.g_info_type_to_string((C.GIInfoType)(typ)))
+}
+
+// BaseInfo is the common base of all introspection info. Use one of the As
+// methods to get at the accessors that only make sense for a particular
+// Type; calling those on the wrong kind of info would crash inside
+// libgirepository, so the conversions check Type first.
+//
+// Infos are unreffed when they are garbage collected, so there's no need
+// to Free them.
+type BaseInfo struct {
+	ptr *C.GIBaseInfo
+	Type InfoType
+}
+
+// the number of infos that haven't been unreffed yet
+var liveInfos int64
+
+func newBaseInfo(ptr *C.GIBaseInfo) *BaseInfo {
+	if ptr == nil {
+		return nil
+	}
+	typ := (InfoType)(C.g_base_info_get_type(ptr))
+	info := &BaseInfo{ptr, typ}
+	atomic.AddInt64(&liveInfos, 1)
+	runtime.SetFinalizer(info, (*BaseInfo).Free)
+	return info
+}
+
+// Free unrefs the info straight away instead of waiting for the garbage
+// collector. It's safe to call more than once, but the info can't be used
+// afterwards.
+func (info *BaseInfo) Free() {
+	if info.ptr == nil {
+		return
+	}
+	C.g_base_info_unref(info.ptr)
+	info.ptr = nil
+	runtime.SetFinalizer(info, nil)
+	atomic.AddInt64(&liveInfos, -1)
+}
+
+// LiveInfos returns the number of infos that haven't been freed, either
+// explicitly or by the garbage collector.
+func LiveInfos() int {
+	return int(atomic.LoadInt64(&liveInfos))
+}
+
+// InfoTypeError is returned when converting an info to a type it isn't.
+type InfoTypeError struct {
+	Name string
+	Type InfoType
+	Want string
+}
+
+func (err InfoTypeError) Error() string {
+	return err.Name + " is " + InfoTypeToString(err.Type) + ", not " + err.Want
+}
+
+func (info *BaseInfo) checkType(want string, types ...InfoType) error {
+	for _, typ := range types {
+		if info.Type == typ {
+			return nil
+		}
+	}
+	return InfoTypeError{Name:info.GetName(), Type:info.Type, Want:want}
+}
+
+type TypeTag C.GITypeTag
+const (
+	VoidTag TypeTag = C.GI_TYPE_TAG_VOID
+	BooleanTag TypeTag = C.GI_TYPE_TAG_BOOLEAN
+	Int8Tag TypeTag = C.GI_TYPE_TAG_INT8
+	Uint8Tag TypeTag = C.GI_TYPE_TAG_UINT8
+	Int16Tag TypeTag = C.GI_TYPE_TAG_INT16
+	Uint16Tag TypeTag = C.GI_TYPE_TAG_UINT16
+	Int32Tag TypeTag = C.GI_TYPE_TAG_INT32
+	Uint32Tag TypeTag = C.GI_TYPE_TAG_UINT32
+	Int64Tag TypeTag = C.GI_TYPE_TAG_INT64
+	Uint64Tag TypeTag = C.GI_TYPE_TAG_UINT64
+	FloatTag TypeTag = C.GI_TYPE_TAG_FLOAT
+	DoubleTag TypeTag = C.GI_TYPE_TAG_DOUBLE
+	GTypeTag TypeTag = C.GI_TYPE_TAG_GTYPE
+	Utf8Tag TypeTag = C.GI_TYPE_TAG_UTF8
+	FilenameTag TypeTag = C.GI_TYPE_TAG_FILENAME
+	// non-basic types
+	ArrayTag TypeTag = C.GI_TYPE_TAG_ARRAY
+	InterfaceTag TypeTag = C.GI_TYPE_TAG_INTERFACE
+	GListTag TypeTag = C.GI_TYPE_TAG_GLIST
+	GSListTag TypeTag = C.GI_TYPE_TAG_GSLIST
+	GHashTag TypeTag = C.GI_TYPE_TAG_GHASH
+	ErrorTag TypeTag = C.GI_TYPE_TAG_ERROR
+	// another basic type
+	UnicharTag TypeTag = C.GI_TYPE_TAG_UNICHAR
+)
+
+/* -- Base Info -- */
+
+func (info *BaseInfo) GetName() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_base_info_get_name(info.ptr))
+}
+
+func (info *BaseInfo) GetFullName() string {
+	defer runtime.KeepAlive(info)
+	return strings.ToLower(goString(C.g_base_info_get_namespace(info.ptr))) + "_" + info.GetName()
+}
+
+func (info *BaseInfo) GetNamespace() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_base_info_get_namespace(info.ptr))
+}
+
+func (info *BaseInfo) IsDeprecated() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_base_info_is_deprecated(info.ptr))
+}
+
+func (info *BaseInfo) GetAttribute(attr string) string {
+	defer runtime.KeepAlive(info)
+	_attr := glibString(attr) ; defer C.g_free((C.gpointer)(_attr))
+	return goString(C.g_base_info_get_attribute(info.ptr, _attr))
+}
+
+// GetAttributes returns every attribute set on the info.
+func (info *BaseInfo) GetAttributes() map[string]string {
+	defer runtime.KeepAlive(info)
+	attrs := make(map[string]string)
+	var iter C.GIAttributeIter
+	var name, value *C.char
+	for goBool(C.g_base_info_iterate_attributes(info.ptr, &iter, &name, &value)) {
+		attrs[C.GoString(name)] = C.GoString(value)
+	}
+	return attrs
+}
+
+// GetContainer returns the info that contains this one, such as the object
+// a method belongs to, or nil for top-level infos.
+func (info *BaseInfo) GetContainer() *BaseInfo {
+	defer runtime.KeepAlive(info)
+	container := C.g_base_info_get_container(info.ptr)
+	if container == nil {
+		return nil
+	}
+	// the container isn't reffed for us
+	return newBaseInfo(C.g_base_info_ref(container))
+}
+
+func (info *BaseInfo) Equal(other *BaseInfo) bool {
+	defer runtime.KeepAlive(info)
+	defer runtime.KeepAlive(other)
+	return goBool(C.g_base_info_equal(info.ptr, other.ptr))
+}
+
+/* -- Callables -- */
+
+type Transfer C.GITransfer
+const (
+	Nothing Transfer = C.GI_TRANSFER_NOTHING
+	Container Transfer = C.GI_TRANSFER_CONTAINER
+	Everything Transfer = C.GI_TRANSFER_EVERYTHING
+)
+
+// String returns the name .gir files use for the transfer.
+func (transfer Transfer) String() string {
+	switch transfer {
+	case Container:
+		return "container"
+	case Everything:
+		return "full"
+	}
+	return "none"
+}
+
+func (transfer Transfer) MarshalText() ([]byte, error) {
+	return []byte(transfer.String()), nil
+}
+
+// CallableInfo is a function, callback, signal or virtual function.
+type CallableInfo struct {
+	*BaseInfo
+}
+
+func (info *BaseInfo) IsCallable() bool {
+	switch info.Type {
+	case Function, Callback, Signal, VFunc:
+		return true
+	}
+	return false
+}
+
+func (info *BaseInfo) AsCallable() (*CallableInfo, error) {
+	if err := info.checkType("a callable", Function, Callback, Signal, VFunc); err != nil {
+		return nil, err
+	}
+	return &CallableInfo{info}, nil
+}
+
+func (info *CallableInfo) callable() *C.GICallableInfo {
+	return (*C.GICallableInfo)(info.ptr)
+}
+
+func (info *CallableInfo) GetReturnType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_callable_info_get_return_type(info.callable()))
+}
+
+func (info *CallableInfo) GetCallerOwns() Transfer {
+	defer runtime.KeepAlive(info)
+	return (Transfer)(C.g_callable_info_get_caller_owns(info.callable()))
+}
+
+func (info *CallableInfo) MayReturnNull() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_callable_info_may_return_null(info.callable()))
+}
+
+func (info *CallableInfo) GetReturnAttribute(name string) string {
+	defer runtime.KeepAlive(info)
+	_name := glibString(name) ; defer C.g_free((C.gpointer)(_name))
+	return goString(C.g_callable_info_get_return_attribute(info.callable(), _name))
+}
+
+// iterate return attributes?
+
+func (info *CallableInfo) GetNArgs() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_callable_info_get_n_args(info.callable()))
+}
+
+func (info *CallableInfo) GetArg(n int) *ArgInfo {
+	defer runtime.KeepAlive(info)
+	return newArgInfo(C.g_callable_info_get_arg(info.callable(), glibInt(n)))
+}
+
+// IsMethod reports whether the callable takes an instance as its first,
+// implicit argument.
+func (info *CallableInfo) IsMethod() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_callable_info_is_method(info.callable()))
+}
+
+func (info *CallableInfo) CanThrowGError() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_callable_info_can_throw_gerror(info.callable()))
+}
+
+// SkipReturn reports whether the return value is only useful in C and
+// should be left out of bindings.
+func (info *CallableInfo) SkipReturn() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_callable_info_skip_return(info.callable()))
+}
+
+/* -- Function Info -- */
+
+type FunctionFlags struct {
+	IsMethod bool
+	IsConstructor bool
+	IsGetter bool
+	IsSetter bool
+	WrapsVFunc bool
+	Throws bool
+}
+
+func newFunctionFlags(bits C.GIFunctionInfoFlags) FunctionFlags {
+	var flags FunctionFlags
+	populateFlags(&flags, (C.gint)(bits), []C.gint{
+		C.GI_FUNCTION_IS_METHOD,
+		C.GI_FUNCTION_IS_CONSTRUCTOR,
+		C.GI_FUNCTION_IS_GETTER,
+		C.GI_FUNCTION_IS_SETTER,
+		C.GI_FUNCTION_WRAPS_VFUNC,
+		C.GI_FUNCTION_THROWS,
+	})
+	return flags
+}
+
+type FunctionInfo struct {
+	CallableInfo
+}
+
+func newFunctionInfo(ptr *C.GIFunctionInfo) *FunctionInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &FunctionInfo{CallableInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsFunction() (*FunctionInfo, error) {
+	if err := info.checkType("a function", Function); err != nil {
+		return nil, err
+	}
+	return &FunctionInfo{CallableInfo{info}}, nil
+}
+
+func (info *FunctionInfo) function() *C.GIFunctionInfo {
+	return (*C.GIFunctionInfo)(info.ptr)
+}
+
+func (info *FunctionInfo) GetSymbol() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_function_info_get_symbol(info.function()))
+}
+
+func (info *FunctionInfo) GetFlags() FunctionFlags {
+	defer runtime.KeepAlive(info)
+	return newFunctionFlags(C.g_function_info_get_flags(info.function()))
+}
+
+// GetProperty returns the property this function is an accessor for, if
+// there is one.
+func (info *FunctionInfo) GetProperty() *PropertyInfo {
+	defer runtime.KeepAlive(info)
+	return newPropertyInfo(C.g_function_info_get_property(info.function()))
+}
+
+// GetVFunc returns the virtual function this function wraps, if there is
+// one.
+func (info *FunctionInfo) GetVFunc() *VFuncInfo {
+	defer runtime.KeepAlive(info)
+	return newVFuncInfo(C.g_function_info_get_vfunc(info.function()))
+}
+
+// Invoke calls the function. Methods take their instance as the first
+// argument, followed by a value for each in and inout parameter. The
+// results are the return value, unless there isn't one, followed by every
+// out and inout parameter.
+//
+// Basic types map to their Go equivalents and enums and flags to integers.
+// Everything else is passed as an unsafe.Pointer, and any Go pointer is
+// taken to be the address of a C value, so generated bindings can be passed
+// as they are. Pointers to Go memory are pinned for the length of the call,
+// but what they point to mustn't hold Go pointers itself. Returned objects
+// are handed back as unsafe.Pointers without touching their references.
+func (info *FunctionInfo) Invoke(args ...interface{}) ([]interface{}, error) {
+	defer runtime.KeepAlive(info)
+	symbol := info.GetSymbol()
+
+	isMethod := info.IsMethod()
+	params := make([]*ArgInfo, info.GetNArgs())
+	nIn, nOut := 0, 0
+	if isMethod {
+		nIn++
+	}
+	for i := range params {
+		params[i] = info.GetArg(i)
+		defer params[i].Free()
+		switch params[i].GetDirection() {
+		case In:
+			nIn++
+		case Out:
+			nOut++
+		case InOut:
+			nIn++
+			nOut++
+		}
+	}
+	if len(args) != nIn {
+		return nil, fmt.Errorf("%s takes %d arguments, not %d", symbol, nIn, len(args))
+	}
+
+	in := newArguments(nIn) ; defer freeArguments(in)
+	out := newArguments(nOut) ; defer freeArguments(out)
+	// what the out pointers point to
+	values := newArguments(nOut) ; defer freeArguments(values)
+	var mem callMemory
+	defer mem.release()
+
+	i, o := 0, 0
+	if isMethod {
+		instance, ok := pointerFromGo(args[0], &mem.pinner)
+		if !ok {
+			return nil, fmt.Errorf("%s: instance must be a pointer, not %T", symbol, args[0])
+		}
+		*(*unsafe.Pointer)(unsafe.Pointer(&in[0])) = instance
+		i++
+	}
+	for _, param := range params {
+		dir := param.GetDirection()
+		if dir != In && param.IsCallerAllocates() {
+			return nil, fmt.Errorf("%s: caller-allocated argument %s isn't supported", symbol, param.GetName())
+		}
+		typ := param.GetType()
+		var err error
+		switch dir {
+		case In:
+			err = argumentFromGo(&in[i], typ, param.GetOwnershipTransfer(), args[i], &mem)
+			i++
+		case Out:
+			*(*unsafe.Pointer)(unsafe.Pointer(&out[o])) = unsafe.Pointer(&values[o])
+			o++
+		case InOut:
+			err = argumentFromGo(&values[o], typ, param.GetOwnershipTransfer(), args[i], &mem)
+			*(*unsafe.Pointer)(unsafe.Pointer(&in[i])) = unsafe.Pointer(&values[o])
+			*(*unsafe.Pointer)(unsafe.Pointer(&out[o])) = unsafe.Pointer(&values[o])
+			i++
+			o++
+		}
+		typ.Free()
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %s: %s", symbol, param.GetName(), err)
+		}
+	}
+
+	var retval C.GIArgument
+	var err *C.GError
+	C.g_function_info_invoke(info.function(), firstArgument(in), C.int(nIn), firstArgument(out), C.int(nOut), &retval, &err)
+	if err != nil {
+		return nil, newGError(err)
+	}
+
+	var results []interface{}
+	ret := info.GetReturnType()
+	defer ret.Free()
+	if !info.SkipReturn() && (ret.GetTag() != VoidTag || ret.IsPointer()) {
+		results = append(results, argumentToGo(&retval, ret, info.GetCallerOwns()))
+	}
+	o = 0
+	for _, param := range params {
+		if param.GetDirection() == In {
+			continue
+		}
+		typ := param.GetType()
+		results = append(results, argumentToGo(&values[o], typ, param.GetOwnershipTransfer()))
+		typ.Free()
+		o++
+	}
+	return results, nil
+}
+
+/* -- Callback Info -- */
+
+// CallbackInfo describes the signature of a function pointer type.
+type CallbackInfo struct {
+	CallableInfo
+}
+
+func (info *BaseInfo) AsCallback() (*CallbackInfo, error) {
+	if err := info.checkType("a callback", Callback); err != nil {
+		return nil, err
+	}
+	return &CallbackInfo{CallableInfo{info}}, nil
+}
+
+/* -- Signal Info -- */
+
+type SignalFlags struct {
+	RunFirst bool
+	RunLast bool
+	RunCleanup bool
+	NoRecurse bool
+	Detailed bool
+	Action bool
+	NoHooks bool
+	MustCollect bool
+	Deprecated bool
+}
+
+func newSignalFlags(bits C.GSignalFlags) *SignalFlags {
+	var flags SignalFlags
+	populateFlags(&flags, (C.gint)(bits), []C.gint{
+		C.G_SIGNAL_RUN_FIRST,
+		C.G_SIGNAL_RUN_LAST,
+		C.G_SIGNAL_RUN_CLEANUP,
+		C.G_SIGNAL_NO_RECURSE,
+		C.G_SIGNAL_DETAILED,
+		C.G_SIGNAL_ACTION,
+		C.G_SIGNAL_NO_HOOKS,
+		C.G_SIGNAL_MUST_COLLECT,
+		C.G_SIGNAL_DEPRECATED,
+	})
+	return &flags
+}
+
+type SignalInfo struct {
+	CallableInfo
+}
+
+func newSignalInfo(ptr *C.GISignalInfo) *SignalInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &SignalInfo{CallableInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsSignal() (*SignalInfo, error) {
+	if err := info.checkType("a signal", Signal); err != nil {
+		return nil, err
+	}
+	return &SignalInfo{CallableInfo{info}}, nil
+}
+
+func (info *SignalInfo) signal() *C.GISignalInfo {
+	return (*C.GISignalInfo)(info.ptr)
+}
+
+func (info *SignalInfo) GetFlags() *SignalFlags {
+	defer runtime.KeepAlive(info)
+	return newSignalFlags(C.g_signal_info_get_flags(info.signal()))
+}
+
+func (info *SignalInfo) GetClassClosure() *VFuncInfo {
+	defer runtime.KeepAlive(info)
+	return newVFuncInfo(C.g_signal_info_get_class_closure(info.signal()))
+}
+
+func (info *SignalInfo) TrueStopsEmit() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_signal_info_true_stops_emit(info.signal()))
+}
+
+/* -- VFunc Info -- */
+
+type VFuncFlags struct {
+	MustChainUp bool
+	MustOverride bool
+	MustNotOverride bool
+	Throws bool
+}
+
+func newVFuncFlags(bits C.GIVFuncInfoFlags) *VFuncFlags {
+	var flags VFuncFlags
+	populateFlags(&flags, (C.gint)(bits), []C.gint{
+		C.GI_VFUNC_MUST_CHAIN_UP,
+		C.GI_VFUNC_MUST_OVERRIDE,
+		C.GI_VFUNC_MUST_NOT_OVERRIDE,
+		C.GI_VFUNC_THROWS,
+	})
+	return &flags
+}
+
+type VFuncInfo struct {
+	CallableInfo
+}
+
+func newVFuncInfo(ptr *C.GIVFuncInfo) *VFuncInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &VFuncInfo{CallableInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsVFunc() (*VFuncInfo, error) {
+	if err := info.checkType("a virtual function", VFunc); err != nil {
+		return nil, err
+	}
+	return &VFuncInfo{CallableInfo{info}}, nil
+}
+
+func (info *VFuncInfo) vfunc() *C.GIVFuncInfo {
+	return (*C.GIVFuncInfo)(info.ptr)
+}
+
+func (info *VFuncInfo) GetFlags() *VFuncFlags {
+	defer runtime.KeepAlive(info)
+	return newVFuncFlags(C.g_vfunc_info_get_flags(info.vfunc()))
+}
+
+func (info *VFuncInfo) GetOffset() int {
+	defer runtime.KeepAlive(info)
+	// TODO: check for a value of 0xFFFF, which means it's unknown
+	return goInt(C.g_vfunc_info_get_offset(info.vfunc()))
+}
+
+func (info *VFuncInfo) GetSignal() *SignalInfo {
+	defer runtime.KeepAlive(info)
+	return newSignalInfo(C.g_vfunc_info_get_signal(info.vfunc()))
+}
+
+func (info *VFuncInfo) GetInvoker() *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_vfunc_info_get_invoker(info.vfunc()))
+}
+
+/* -- RegisteredType Info -- */
+
+// RegisteredTypeInfo is an enum, interface, object, struct or union.
+type RegisteredTypeInfo struct {
+	*BaseInfo
+}
+
+func (info *BaseInfo) IsRegisteredType() bool {
+	switch info.Type {
+	case Enum, Flags, Interface, Object, Struct, Boxed, Union:
+		return true
+	}
+	return false
+}
+
+func (info *BaseInfo) AsRegisteredType() (*RegisteredTypeInfo, error) {
+	if err := info.checkType("a registered type", Enum, Flags, Interface, Object, Struct, Boxed, Union); err != nil {
+		return nil, err
+	}
+	return &RegisteredTypeInfo{info}, nil
+}
+
+func (info *RegisteredTypeInfo) registeredType() *C.GIRegisteredTypeInfo {
+	return (*C.GIRegisteredTypeInfo)(info.ptr)
+}
+
+func (info *RegisteredTypeInfo) GetTypeName() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_registered_type_info_get_type_name(info.registeredType()))
+}
+
+func (info *RegisteredTypeInfo) GetTypeInit() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_registered_type_info_get_type_init(info.registeredType()))
+}
+
+type GType uintptr
+
+func (info *RegisteredTypeInfo) GetGType() GType {
+	defer runtime.KeepAlive(info)
+	return (GType)(C.g_registered_type_info_get_g_type(info.registeredType()))
+}
+
+/* -- Enum Info -- */
+
+// EnumInfo is an enum or a set of flags.
+type EnumInfo struct {
+	RegisteredTypeInfo
+}
+
+func (info *BaseInfo) AsEnum() (*EnumInfo, error) {
+	if err := info.checkType("an enum", Enum, Flags); err != nil {
+		return nil, err
+	}
+	return &EnumInfo{RegisteredTypeInfo{info}}, nil
+}
+
+func (info *EnumInfo) enum() *C.GIEnumInfo {
+	return (*C.GIEnumInfo)(info.ptr)
+}
+
+func (info *EnumInfo) GetNValues() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_enum_info_get_n_values(info.enum()))
+}
+
+func (info *EnumInfo) GetValue(n int) *ValueInfo {
+	defer runtime.KeepAlive(info)
+	return newValueInfo(C.g_enum_info_get_value(info.enum(), glibInt(n)))
+}
+
+func (info *EnumInfo) GetNMethods() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_enum_info_get_n_methods(info.enum()))
+}
+
+func (info *EnumInfo) GetMethod(n int) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_enum_info_get_method(info.enum(), glibInt(n)))
+}
+
+func (info *EnumInfo) GetStorageType() TypeTag {
+	defer runtime.KeepAlive(info)
+	return (TypeTag)(C.g_enum_info_get_storage_type(info.enum()))
+}
+
+// GetErrorDomain returns the name of the error domain the enum holds the
+// codes of, or "" if it isn't one.
+func (info *EnumInfo) GetErrorDomain() string {
+	defer runtime.KeepAlive(info)
+	domain := C.g_enum_info_get_error_domain(info.enum())
+	if domain == nil {
+		return ""
+	}
+	return goString(domain)
+}
+
+// ValueInfo is a single value of an enum.
+type ValueInfo struct {
+	*BaseInfo
+}
+
+func newValueInfo(ptr *C.GIValueInfo) *ValueInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &ValueInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsValue() (*ValueInfo, error) {
+	if err := info.checkType("an enum value", Value); err != nil {
+		return nil, err
+	}
+	return &ValueInfo{info}, nil
+}
+
+func (info *ValueInfo) GetValue() int64 {
+	defer runtime.KeepAlive(info)
+	return (int64)(C.g_value_info_get_value((*C.GIValueInfo)(info.ptr)))
+}
+
+/* -- Struct Info -- */
+
+type StructInfo struct {
+	RegisteredTypeInfo
+}
+
+func newStructInfo(ptr *C.GIStructInfo) *StructInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &StructInfo{RegisteredTypeInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsStruct() (*StructInfo, error) {
+	if err := info.checkType("a struct", Struct); err != nil {
+		return nil, err
+	}
+	return &StructInfo{RegisteredTypeInfo{info}}, nil
+}
+
+func (info *StructInfo) structInfo() *C.GIStructInfo {
+	return (*C.GIStructInfo)(info.ptr)
+}
+
+func (info *StructInfo) GetNFields() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_struct_info_get_n_fields(info.structInfo()))
+}
+
+func (info *StructInfo) GetField(n int) *FieldInfo {
+	defer runtime.KeepAlive(info)
+	return newFieldInfo(C.g_struct_info_get_field(info.structInfo(), glibInt(n)))
+}
+
+func (info *StructInfo) GetNMethods() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_struct_info_get_n_methods(info.structInfo()))
+}
+
+func (info *StructInfo) GetMethod(n int) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_struct_info_get_method(info.structInfo(), glibInt(n)))
+}
+
+func (info *StructInfo) FindMethod(name string) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	_name := glibString(name) ; defer freeString(_name)
+	return newFunctionInfo(C.g_struct_info_find_method(info.structInfo(), _name))
+}
+
+func (info *StructInfo) GetSize() int {
+	defer runtime.KeepAlive(info)
+	return int(C.g_struct_info_get_size(info.structInfo()))
+}
+
+func (info *StructInfo) GetAlignment() int {
+	defer runtime.KeepAlive(info)
+	return int(C.g_struct_info_get_alignment(info.structInfo()))
+}
+
+func (info *StructInfo) IsGTypeStruct() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_struct_info_is_gtype_struct(info.structInfo()))
+}
+
+func (info *StructInfo) IsForeign() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_struct_info_is_foreign(info.structInfo()))
+}
+
+/* -- Union Info -- */
+
+type UnionInfo struct {
+	RegisteredTypeInfo
+}
+
+func (info *BaseInfo) AsUnion() (*UnionInfo, error) {
+	if err := info.checkType("a union", Union); err != nil {
+		return nil, err
+	}
+	return &UnionInfo{RegisteredTypeInfo{info}}, nil
+}
+
+func (info *UnionInfo) union() *C.GIUnionInfo {
+	return (*C.GIUnionInfo)(info.ptr)
+}
+
+func (info *UnionInfo) GetNFields() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_union_info_get_n_fields(info.union()))
+}
+
+func (info *UnionInfo) GetField(n int) *FieldInfo {
+	defer runtime.KeepAlive(info)
+	return newFieldInfo(C.g_union_info_get_field(info.union(), glibInt(n)))
+}
+
+func (info *UnionInfo) GetNMethods() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_union_info_get_n_methods(info.union()))
+}
+
+func (info *UnionInfo) GetMethod(n int) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_union_info_get_method(info.union(), glibInt(n)))
+}
+
+func (info *UnionInfo) FindMethod(name string) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	_name := glibString(name) ; defer freeString(_name)
+	return newFunctionInfo(C.g_union_info_find_method(info.union(), _name))
+}
+
+func (info *UnionInfo) IsDiscriminated() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_union_info_is_discriminated(info.union()))
+}
+
+func (info *UnionInfo) GetDiscriminatorOffset() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_union_info_get_discriminator_offset(info.union()))
+}
+
+func (info *UnionInfo) GetDiscriminatorType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_union_info_get_discriminator_type(info.union()))
+}
+
+// GetDiscriminator returns the value of the discriminator that selects
+// field n.
+func (info *UnionInfo) GetDiscriminator(n int) *ConstantInfo {
+	defer runtime.KeepAlive(info)
+	return newConstantInfo(C.g_union_info_get_discriminator(info.union(), glibInt(n)))
+}
+
+func (info *UnionInfo) GetSize() int {
+	defer runtime.KeepAlive(info)
+	return int(C.g_union_info_get_size(info.union()))
+}
+
+func (info *UnionInfo) GetAlignment() int {
+	defer runtime.KeepAlive(info)
+	return int(C.g_union_info_get_alignment(info.union()))
+}
+
+/* -- Object Info -- */
+
+type ObjectInfo struct {
+	RegisteredTypeInfo
+}
+
+func newObjectInfo(ptr *C.GIObjectInfo) *ObjectInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &ObjectInfo{RegisteredTypeInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsObject() (*ObjectInfo, error) {
+	if err := info.checkType("an object", Object); err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{RegisteredTypeInfo{info}}, nil
+}
+
+func (info *ObjectInfo) object() *C.GIObjectInfo {
+	return (*C.GIObjectInfo)(info.ptr)
+}
+
+func (info *ObjectInfo) GetTypeName() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_object_info_get_type_name(info.object()))
+}
+
+func (info *ObjectInfo) GetTypeInit() string {
+	defer runtime.KeepAlive(info)
+	return goString(C.g_object_info_get_type_init(info.object()))
+}
+
+func (info *ObjectInfo) IsAbstract() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_object_info_get_abstract(info.object()))
+}
+
+func (info *ObjectInfo) IsFundamental() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_object_info_get_fundamental(info.object()))
+}
+
+// GetParent returns nil for objects without a parent, such as GObject
+// itself and other fundamental types.
+func (info *ObjectInfo) GetParent() *ObjectInfo {
+	defer runtime.KeepAlive(info)
+	return newObjectInfo(C.g_object_info_get_parent(info.object()))
+}
+
+func (info *ObjectInfo) GetNInterfaces() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_interfaces(info.object()))
+}
+
+func (info *ObjectInfo) GetInterface(n int) *InterfaceInfo {
+	defer runtime.KeepAlive(info)
+	return newInterfaceInfo(C.g_object_info_get_interface(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) GetNFields() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_fields(info.object()))
+}
+
+func (info *ObjectInfo) GetField(n int) *FieldInfo {
+	defer runtime.KeepAlive(info)
+	return newFieldInfo(C.g_object_info_get_field(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) GetNProperties() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_properties(info.object()))
+}
+
+func (info *ObjectInfo) GetProperty(n int) *PropertyInfo {
+	defer runtime.KeepAlive(info)
+	return newPropertyInfo(C.g_object_info_get_property(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) GetNMethods() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_methods(info.object()))
+}
+
+func (info *ObjectInfo) GetMethod(n int) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_object_info_get_method(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) FindMethod(name string) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	_name := glibString(name) ; defer freeString(_name)
+	return newFunctionInfo(C.g_object_info_find_method(info.object(), _name))
+}
+
+func (info *ObjectInfo) GetNSignals() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_signals(info.object()))
+}
+
+func (info *ObjectInfo) GetSignal(n int) *SignalInfo {
+	defer runtime.KeepAlive(info)
+	return newSignalInfo(C.g_object_info_get_signal(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) GetNVFuncs() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_vfuncs(info.object()))
+}
+
+func (info *ObjectInfo) GetVFunc(n int) *VFuncInfo {
+	defer runtime.KeepAlive(info)
+	return newVFuncInfo(C.g_object_info_get_vfunc(info.object(), glibInt(n)))
+}
+
+func (info *ObjectInfo) GetNConstants() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_object_info_get_n_constants(info.object()))
+}
+
+func (info *ObjectInfo) GetConstant(n int) *ConstantInfo {
+	defer runtime.KeepAlive(info)
+	return newConstantInfo(C.g_object_info_get_constant(info.object(), glibInt(n)))
+}
+
+// GetClassStruct returns the struct holding the class's virtual functions.
+func (info *ObjectInfo) GetClassStruct() *StructInfo {
+	defer runtime.KeepAlive(info)
+	return newStructInfo(C.g_object_info_get_class_struct(info.object()))
+}
+
+/* -- Interface Info -- */
+
+type InterfaceInfo struct {
+	RegisteredTypeInfo
+}
+
+func newInterfaceInfo(ptr *C.GIInterfaceInfo) *InterfaceInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &InterfaceInfo{RegisteredTypeInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}}
+}
+
+func (info *BaseInfo) AsInterface() (*InterfaceInfo, error) {
+	if err := info.checkType("an interface", Interface); err != nil {
+		return nil, err
+	}
+	return &InterfaceInfo{RegisteredTypeInfo{info}}, nil
+}
+
+func (info *InterfaceInfo) iface() *C.GIInterfaceInfo {
+	return (*C.GIInterfaceInfo)(info.ptr)
+}
+
+func (info *InterfaceInfo) GetNPrerequisites() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_prerequisites(info.iface()))
+}
+
+// GetPrerequisite returns an object or interface that implementations of
+// this interface must also be.
+func (info *InterfaceInfo) GetPrerequisite(n int) *BaseInfo {
+	defer runtime.KeepAlive(info)
+	return newBaseInfo(C.g_interface_info_get_prerequisite(info.iface(), glibInt(n)))
+}
+
+func (info *InterfaceInfo) GetNProperties() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_properties(info.iface()))
+}
+
+func (info *InterfaceInfo) GetProperty(n int) *PropertyInfo {
+	defer runtime.KeepAlive(info)
+	return newPropertyInfo(C.g_interface_info_get_property(info.iface(), glibInt(n)))
+}
+
+func (info *InterfaceInfo) GetNMethods() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_methods(info.iface()))
+}
+
+func (info *InterfaceInfo) GetMethod(n int) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_interface_info_get_method(info.iface(), glibInt(n)))
+}
+
+func (info *InterfaceInfo) FindMethod(name string) *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	_name := glibString(name) ; defer freeString(_name)
+	return newFunctionInfo(C.g_interface_info_find_method(info.iface(), _name))
+}
+
+func (info *InterfaceInfo) GetNSignals() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_signals(info.iface()))
+}
+
+func (info *InterfaceInfo) GetSignal(n int) *SignalInfo {
+	defer runtime.KeepAlive(info)
+	return newSignalInfo(C.g_interface_info_get_signal(info.iface(), glibInt(n)))
+}
+
+func (info *InterfaceInfo) GetNVFuncs() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_vfuncs(info.iface()))
+}
+
+func (info *InterfaceInfo) GetVFunc(n int) *VFuncInfo {
+	defer runtime.KeepAlive(info)
+	return newVFuncInfo(C.g_interface_info_get_vfunc(info.iface(), glibInt(n)))
+}
+
+func (info *InterfaceInfo) GetNConstants() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_interface_info_get_n_constants(info.iface()))
+}
+
+func (info *InterfaceInfo) GetConstant(n int) *ConstantInfo {
+	defer runtime.KeepAlive(info)
+	return newConstantInfo(C.g_interface_info_get_constant(info.iface(), glibInt(n)))
+}
+
+// GetIfaceStruct returns the struct holding the interface's virtual
+// functions.
+func (info *InterfaceInfo) GetIfaceStruct() *StructInfo {
+	defer runtime.KeepAlive(info)
+	return newStructInfo(C.g_interface_info_get_iface_struct(info.iface()))
+}
+
+/* -- Constant Info -- */
+
+type ConstantInfo struct {
+	*BaseInfo
+}
+
+func newConstantInfo(ptr *C.GIConstantInfo) *ConstantInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &ConstantInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsConstant() (*ConstantInfo, error) {
+	if err := info.checkType("a constant", Constant); err != nil {
+		return nil, err
+	}
+	return &ConstantInfo{info}, nil
+}
+
+func (info *ConstantInfo) constant() *C.GIConstantInfo {
+	return (*C.GIConstantInfo)(info.ptr)
+}
+
+func (info *ConstantInfo) GetType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_constant_info_get_type(info.constant()))
+}
+
+// GetValue returns the value of the constant as the Go type matching its
+// type tag, i.e. an int32 for Int32Tag or a string for Utf8Tag.
+func (info *ConstantInfo) GetValue() interface{} {
+	defer runtime.KeepAlive(info)
+	tag := info.GetType().GetTag()
+	var arg C.GIArgument
+	C.g_constant_info_get_value(info.constant(), &arg)
+	defer C.g_constant_info_free_value(info.constant(), &arg)
+	return goFromArgument(&arg, tag)
+}
+
+/* -- Property Info -- */
+
+type PropertyFlags struct {
+	Readable bool
+	Writable bool
+	Construct bool
+	ConstructOnly bool
+}
+
+func newPropertyFlags(bits C.GParamFlags) PropertyFlags {
+	var flags PropertyFlags
+	populateFlags(&flags, (C.gint)(bits), []C.gint{
+		C.G_PARAM_READABLE,
+		C.G_PARAM_WRITABLE,
+		C.G_PARAM_CONSTRUCT,
+		C.G_PARAM_CONSTRUCT_ONLY,
+	})
+	return flags
+}
+
+type PropertyInfo struct {
+	*BaseInfo
+}
+
+func newPropertyInfo(ptr *C.GIPropertyInfo) *PropertyInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &PropertyInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsProperty() (*PropertyInfo, error) {
+	if err := info.checkType("a property", Property); err != nil {
+		return nil, err
+	}
+	return &PropertyInfo{info}, nil
+}
+
+func (info *PropertyInfo) property() *C.GIPropertyInfo {
+	return (*C.GIPropertyInfo)(info.ptr)
+}
+
+func (info *PropertyInfo) GetFlags() PropertyFlags {
+	defer runtime.KeepAlive(info)
+	return newPropertyFlags(C.g_property_info_get_flags(info.property()))
+}
+
+func (info *PropertyInfo) GetType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_property_info_get_type(info.property()))
+}
+
+func (info *PropertyInfo) GetOwnershipTransfer() Transfer {
+	defer runtime.KeepAlive(info)
+	return (Transfer)(C.g_property_info_get_ownership_transfer(info.property()))
+}
+
+// GetGetter returns the method used to read the property, if it has one.
+func (info *PropertyInfo) GetGetter() *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_property_info_get_getter(info.property()))
+}
+
+// GetSetter returns the method used to write the property, if it has one.
+func (info *PropertyInfo) GetSetter() *FunctionInfo {
+	defer runtime.KeepAlive(info)
+	return newFunctionInfo(C.g_property_info_get_setter(info.property()))
+}
+
+/* -- Field Info -- */
+
+type FieldFlags struct {
+	Readable bool
+	Writable bool
+}
+
+func newFieldFlags(bits C.GIFieldInfoFlags) FieldFlags {
+	var flags FieldFlags
+	populateFlags(&flags, (C.gint)(bits), []C.gint{
+		C.GI_FIELD_IS_READABLE,
+		C.GI_FIELD_IS_WRITABLE,
+	})
+	return flags
+}
+
+type FieldInfo struct {
+	*BaseInfo
+}
+
+func newFieldInfo(ptr *C.GIFieldInfo) *FieldInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &FieldInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsField() (*FieldInfo, error) {
+	if err := info.checkType("a field", Field); err != nil {
+		return nil, err
+	}
+	return &FieldInfo{info}, nil
+}
+
+func (info *FieldInfo) field() *C.GIFieldInfo {
+	return (*C.GIFieldInfo)(info.ptr)
+}
+
+func (info *FieldInfo) GetFlags() FieldFlags {
+	defer runtime.KeepAlive(info)
+	return newFieldFlags(C.g_field_info_get_flags(info.field()))
+}
+
+// GetOffset returns the offset of the field in bytes.
+func (info *FieldInfo) GetOffset() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_field_info_get_offset(info.field()))
+}
+
+// GetSize returns the size of the field in bits, which is only set for
+// bitfields.
+func (info *FieldInfo) GetSize() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_field_info_get_size(info.field()))
+}
+
+func (info *FieldInfo) GetType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_field_info_get_type(info.field()))
+}
+
+/* -- Arg Info -- */
+
+type Direction C.GIDirection
+const (
+	In Direction = C.GI_DIRECTION_IN
+	Out Direction = C.GI_DIRECTION_OUT
+	InOut Direction = C.GI_DIRECTION_INOUT
+)
+
+// String returns the name .gir files use for the direction.
+func (dir Direction) String() string {
+	switch dir {
+	case Out:
+		return "out"
+	case InOut:
+		return "inout"
+	}
+	return "in"
+}
+
+func (dir Direction) MarshalText() ([]byte, error) {
+	return []byte(dir.String()), nil
+}
+
+// ScopeType says how long a callback argument stays valid. The constants
+// carry a Scope suffix so they don't collide with Call.
+type ScopeType C.GIScopeType
+const (
+	InvalidScope ScopeType = C.GI_SCOPE_TYPE_INVALID
+	CallScope ScopeType = C.GI_SCOPE_TYPE_CALL
+	AsyncScope ScopeType = C.GI_SCOPE_TYPE_ASYNC
+	NotifiedScope ScopeType = C.GI_SCOPE_TYPE_NOTIFIED
+	ForeverScope ScopeType = C.GI_SCOPE_TYPE_FOREVER
+)
+
+type ArgInfo struct {
+	*BaseInfo
+}
+
+func newArgInfo(ptr *C.GIArgInfo) *ArgInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &ArgInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsArg() (*ArgInfo, error) {
+	if err := info.checkType("an argument", Arg); err != nil {
+		return nil, err
+	}
+	return &ArgInfo{info}, nil
+}
+
+func (info *ArgInfo) arg() *C.GIArgInfo {
+	return (*C.GIArgInfo)(info.ptr)
+}
+
+func (info *ArgInfo) GetDirection() Direction {
+	defer runtime.KeepAlive(info)
+	return (Direction)(C.g_arg_info_get_direction(info.arg()))
+}
+
+func (info *ArgInfo) IsCallerAllocates() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_arg_info_is_caller_allocates(info.arg()))
+}
+
+func (info *ArgInfo) IsReturnValue() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_arg_info_is_return_value(info.arg()))
+}
+
+func (info *ArgInfo) IsOptional() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_arg_info_is_optional(info.arg()))
+}
+
+func (info *ArgInfo) MayBeNull() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_arg_info_may_be_null(info.arg()))
+}
+
+func (info *ArgInfo) GetOwnershipTransfer() Transfer {
+	defer runtime.KeepAlive(info)
+	return (Transfer)(C.g_arg_info_get_ownership_transfer(info.arg()))
+}
+
+func (info *ArgInfo) GetScope() ScopeType {
+	defer runtime.KeepAlive(info)
+	return (ScopeType)(C.g_arg_info_get_scope(info.arg()))
+}
+
+// GetClosure returns the index of the user data argument that goes with
+// this callback argument, or -1 if there isn't one.
+func (info *ArgInfo) GetClosure() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_arg_info_get_closure(info.arg()))
+}
+
+// GetDestroy returns the index of the argument that frees the user data of
+// this callback argument, or -1 if there isn't one.
+func (info *ArgInfo) GetDestroy() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_arg_info_get_destroy(info.arg()))
+}
+
+// IsSkip reports whether the argument is only useful in C and should be
+// left out of bindings.
+func (info *ArgInfo) IsSkip() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_arg_info_is_skip(info.arg()))
+}
+
+func (info *ArgInfo) GetType() *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_arg_info_get_type(info.arg()))
+}
+
+/* -- Type Info -- */
+
+type ArrayType C.GIArrayType
+const (
+	CArray ArrayType = C.GI_ARRAY_TYPE_C
+	GArray ArrayType = C.GI_ARRAY_TYPE_ARRAY
+	PtrArray ArrayType = C.GI_ARRAY_TYPE_PTR_ARRAY
+	ByteArray ArrayType = C.GI_ARRAY_TYPE_BYTE_ARRAY
+)
+
+func TypeTagToString(tag TypeTag) string {
+	return goString(C.g_type_tag_to_string((C.GITypeTag)(tag)))
+}
+
+func (tag TypeTag) String() string {
+	return TypeTagToString(tag)
+}
+
+func (tag TypeTag) MarshalText() ([]byte, error) {
+	return []byte(TypeTagToString(tag)), nil
+}
+
+type TypeInfo struct {
+	*BaseInfo
+}
+
+func newTypeInfo(ptr *C.GITypeInfo) *TypeInfo {
+	if ptr == nil {
+		return nil
+	}
+	return &TypeInfo{newBaseInfo((*C.GIBaseInfo)(ptr))}
+}
+
+func (info *BaseInfo) AsType() (*TypeInfo, error) {
+	if err := info.checkType("a type", Type); err != nil {
+		return nil, err
+	}
+	return &TypeInfo{info}, nil
+}
+
+func (info *TypeInfo) typeInfo() *C.GITypeInfo {
+	return (*C.GITypeInfo)(info.ptr)
+}
+
+func (info *TypeInfo) IsPointer() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_type_info_is_pointer(info.typeInfo()))
+}
+
+func (info *TypeInfo) GetTag() TypeTag {
+	defer runtime.KeepAlive(info)
+	return (TypeTag)(C.g_type_info_get_tag(info.typeInfo()))
+}
+
+func (info *TypeInfo) GetParamType(n int) *TypeInfo {
+	defer runtime.KeepAlive(info)
+	return newTypeInfo(C.g_type_info_get_param_type(info.typeInfo(), glibInt(n)))
+}
+
+// GetInterface returns the info the type refers to if its tag is
+// InterfaceTag, and nil otherwise.
+func (info *TypeInfo) GetInterface() *BaseInfo {
+	defer runtime.KeepAlive(info)
+	return newBaseInfo(C.g_type_info_get_interface(info.typeInfo()))
+}
+
+func (info *TypeInfo) GetArrayLength() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_type_info_get_array_length(info.typeInfo()))
+}
+
+func (info *TypeInfo) GetArrayFixedSize() int {
+	defer runtime.KeepAlive(info)
+	return goInt(C.g_type_info_get_array_fixed_size(info.typeInfo()))
+}
+
+func (info *TypeInfo) IsZeroTerminated() bool {
+	defer runtime.KeepAlive(info)
+	return goBool(C.g_type_info_is_zero_terminated(info.typeInfo()))
+}
+
+func (info *TypeInfo) GetArrayType() ArrayType {
+	defer runtime.KeepAlive(info)
+	return (ArrayType)(C.g_type_info_get_array_type(info.typeInfo()))
+}
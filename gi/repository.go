@@ -0,0 +1,382 @@
+package gi
+
+/*
+#cgo pkg-config: glib-2.0 gobject-2.0 gobject-introspection-1.0
+#include <stdlib.h>
+#include <glib.h>
+#include <girepository.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Repository holds the loaded typelibs. It's safe to use from several
+// goroutines at once: loading a namespace waits for any queries in
+// progress, while queries can run side by side.
+type Repository struct {
+	ptr *C.GIRepository
+
+	// libgirepository keeps its namespaces in plain hash tables
+	lock sync.RWMutex
+
+	prefixLock sync.Mutex
+	prefixes map[string] string
+}
+
+var defaultRepository struct {
+	once sync.Once
+	repo *Repository
+}
+
+// DefaultRepository returns the process-wide repository, which is the only
+// one libgirepository supports. The package-level functions all use it.
+func DefaultRepository() *Repository {
+	defaultRepository.once.Do(func() {
+		defaultRepository.repo = &Repository{
+			ptr: C.g_irepository_get_default(),
+			prefixes: make(map[string] string),
+		}
+	})
+	return defaultRepository.repo
+}
+
+// Typelib is a loaded typelib.
+type Typelib struct {
+	ptr *C.GITypelib
+}
+
+// Free frees the typelib. Only use this on typelibs that the repository
+// doesn't own.
+func (typelib *Typelib) Free() {
+	C.g_typelib_free(typelib.ptr)
+}
+
+// Require makes sure namespace is loaded, along with everything it depends
+// on. An empty version loads the latest one available.
+func (repo *Repository) Require(namespace, version string) (*Typelib, error) {
+	ns := glibString(namespace) ; defer freeString(ns)
+	var v *C.gchar = nil
+	if version != "" {
+		v = glibString(version) ; defer freeString(v)
+	}
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	var err *C.GError
+	typelib := C.g_irepository_require(repo.ptr, ns, v, 0, &err)
+	if err != nil {
+		return nil, newGError(err)
+	}
+	return &Typelib{typelib}, nil
+}
+
+// LoadTypelibFile loads a compiled typelib straight from a file, returning
+// the name of the namespace it defines. The typelib is owned by the
+// repository from then on.
+func (repo *Repository) LoadTypelibFile(filename string) (string, error) {
+	f := C.CString(filename) ; defer C.free(unsafe.Pointer(f))
+	var err *C.GError
+	mapped := C.g_mapped_file_new(f, glibBool(false), &err)
+	if err != nil {
+		return "", newGError(err)
+	}
+	// the typelib takes ownership of the mapped file
+	typelib := C.g_typelib_new_from_mapped_file(mapped, &err)
+	if err != nil {
+		C.g_mapped_file_unref(mapped)
+		return "", newGError(err)
+	}
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	ns := C.g_irepository_load_typelib(repo.ptr, typelib, 0, &err)
+	if err != nil {
+		C.g_typelib_free(typelib)
+		return "", newGError(err)
+	}
+	return goString(ns), nil
+}
+
+// PrependSearchPath adds a directory to the front of the list searched for
+// typelibs.
+func (repo *Repository) PrependSearchPath(dir string) {
+	d := C.CString(dir) ; defer C.free(unsafe.Pointer(d))
+	// the search path is global, but is only read while loading
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	C.g_irepository_prepend_search_path(d)
+}
+
+// PrependLibraryPath adds a directory to the front of the list searched for
+// the shared libraries that typelibs refer to.
+func (repo *Repository) PrependLibraryPath(dir string) {
+	d := C.CString(dir) ; defer C.free(unsafe.Pointer(d))
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	C.g_irepository_prepend_library_path(d)
+}
+
+func (repo *Repository) GetNumInfos(namespace string) int {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return goInt(C.g_irepository_get_n_infos(repo.ptr, ns))
+}
+
+func (repo *Repository) GetInfo(namespace string, index int) *BaseInfo {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return newBaseInfo(C.g_irepository_get_info(repo.ptr, ns, glibInt(index)))
+}
+
+// FindByName looks up an info by name within a loaded namespace, returning
+// nil if there isn't one.
+func (repo *Repository) FindByName(namespace, name string) *BaseInfo {
+	ns := glibString(namespace) ; defer freeString(ns)
+	_name := glibString(name) ; defer freeString(_name)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return newBaseInfo(C.g_irepository_find_by_name(repo.ptr, ns, _name))
+}
+
+// FindByGType looks up the info for a registered type among the loaded
+// namespaces, returning nil if none of them describe it.
+func (repo *Repository) FindByGType(gtype GType) *BaseInfo {
+	// the lookup caches its result
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	return newBaseInfo(C.g_irepository_find_by_gtype(repo.ptr, (C.GType)(gtype)))
+}
+
+// GetDependencies returns the full transitive list of namespaces that
+// namespace depends on, in the form "Name-Version".
+func (repo *Repository) GetDependencies(namespace string) []string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	deps := C.g_irepository_get_dependencies(repo.ptr, ns)
+	if deps == nil {
+		return nil
+	}
+	defer C.g_strfreev(deps)
+	return goStringArray(deps)
+}
+
+// GetImmediateDependencies returns only the namespaces that namespace
+// includes directly, in the form "Name-Version".
+func (repo *Repository) GetImmediateDependencies(namespace string) []string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	deps := C.g_irepository_get_immediate_dependencies(repo.ptr, ns)
+	if deps == nil {
+		return nil
+	}
+	defer C.g_strfreev(deps)
+	return goStringArray(deps)
+}
+
+// GetLoadedNamespaces returns the names of every namespace loaded so far.
+func (repo *Repository) GetLoadedNamespaces() []string {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	namespaces := C.g_irepository_get_loaded_namespaces(repo.ptr)
+	defer C.g_strfreev(namespaces)
+	return goStringArray(namespaces)
+}
+
+// GetVersion returns the version of a loaded namespace.
+func (repo *Repository) GetVersion(namespace string) string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return goString(C.g_irepository_get_version(repo.ptr, ns))
+}
+
+// EnumerateVersions returns every version of namespace that could be
+// loaded from the search path.
+func (repo *Repository) EnumerateVersions(namespace string) []string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	versions := C.g_irepository_enumerate_versions(repo.ptr, ns)
+	repo.lock.RUnlock()
+	defer C.g_list_free(versions)
+	var result []string
+	for e := gListToGo(versions).Front(); e != nil; e = e.Next() {
+		version := (*C.gchar)(e.Value.(C.gpointer))
+		result = append(result, goString(version))
+		freeString(version)
+	}
+	return result
+}
+
+// GetSharedLibrary returns the shared libraries that hold the symbols of a
+// loaded namespace, such as "libgtk-3.so.0".
+func (repo *Repository) GetSharedLibrary(namespace string) []string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	libs := C.g_irepository_get_shared_library(repo.ptr, ns)
+	if libs == nil {
+		return nil
+	}
+	return strings.Split(goString(libs), ",")
+}
+
+// GetTypelibPath returns the file a loaded namespace was read from.
+func (repo *Repository) GetTypelibPath(namespace string) string {
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return goString(C.g_irepository_get_typelib_path(repo.ptr, ns))
+}
+
+func (repo *Repository) GetCPrefix(namespace string) string {
+	repo.prefixLock.Lock()
+	defer repo.prefixLock.Unlock()
+	prefix, ok := repo.prefixes[namespace]
+	if ok {
+		return prefix
+	}
+	ns := glibString(namespace) ; defer freeString(ns)
+	repo.lock.RLock()
+	prefix = goString(C.g_irepository_get_c_prefix(repo.ptr, ns))
+	repo.lock.RUnlock()
+	repo.prefixes[namespace] = prefix
+	return prefix
+}
+
+// Call invokes a function by name, loading its namespace first if needed.
+// container is the object, interface, struct or union that the function
+// belongs to, or "" for functions at the top level of the namespace.
+func (repo *Repository) Call(namespace, container, name string, args ...interface{}) ([]interface{}, error) {
+	fn, err := repo.findFunction(namespace, container, name)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Free()
+	return fn.Invoke(args...)
+}
+
+func (repo *Repository) findFunction(namespace, container, name string) (*FunctionInfo, error) {
+	if _, err := repo.Require(namespace, ""); err != nil {
+		return nil, err
+	}
+	if container == "" {
+		info := repo.FindByName(namespace, name)
+		if info == nil {
+			return nil, fmt.Errorf("%s has no function named %s", namespace, name)
+		}
+		fn, err := info.AsFunction()
+		if err != nil {
+			info.Free()
+			return nil, err
+		}
+		return fn, nil
+	}
+
+	info := repo.FindByName(namespace, container)
+	if info == nil {
+		return nil, fmt.Errorf("%s has no type named %s", namespace, container)
+	}
+	defer info.Free()
+	var fn *FunctionInfo
+	switch info.Type {
+	case Object:
+		obj, _ := info.AsObject()
+		fn = obj.FindMethod(name)
+	case Interface:
+		iface, _ := info.AsInterface()
+		fn = iface.FindMethod(name)
+	case Struct:
+		st, _ := info.AsStruct()
+		fn = st.FindMethod(name)
+	case Union:
+		union, _ := info.AsUnion()
+		fn = union.FindMethod(name)
+	default:
+		return nil, InfoTypeError{Name:container, Type:info.Type, Want:"a type with methods"}
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("%s.%s has no method named %s", namespace, container, name)
+	}
+	return fn, nil
+}
+
+/* -- Shortcuts for the default repository -- */
+
+// LoadNamespace makes sure namespace is loaded, along with everything it
+// depends on. An empty version loads the latest one available.
+func LoadNamespace(namespace, version string) (*Typelib, error) {
+	return DefaultRepository().Require(namespace, version)
+}
+
+func LoadTypelibFile(filename string) (string, error) {
+	return DefaultRepository().LoadTypelibFile(filename)
+}
+
+func PrependSearchPath(dir string) {
+	DefaultRepository().PrependSearchPath(dir)
+}
+
+func PrependLibraryPath(dir string) {
+	DefaultRepository().PrependLibraryPath(dir)
+}
+
+func GetNumInfos(namespace string) int {
+	return DefaultRepository().GetNumInfos(namespace)
+}
+
+func GetInfo(namespace string, index int) *BaseInfo {
+	return DefaultRepository().GetInfo(namespace, index)
+}
+
+func FindByName(namespace, name string) *BaseInfo {
+	return DefaultRepository().FindByName(namespace, name)
+}
+
+func FindByGType(gtype GType) *BaseInfo {
+	return DefaultRepository().FindByGType(gtype)
+}
+
+func GetDependencies(namespace string) []string {
+	return DefaultRepository().GetDependencies(namespace)
+}
+
+func GetImmediateDependencies(namespace string) []string {
+	return DefaultRepository().GetImmediateDependencies(namespace)
+}
+
+func GetLoadedNamespaces() []string {
+	return DefaultRepository().GetLoadedNamespaces()
+}
+
+func GetVersion(namespace string) string {
+	return DefaultRepository().GetVersion(namespace)
+}
+
+func EnumerateVersions(namespace string) []string {
+	return DefaultRepository().EnumerateVersions(namespace)
+}
+
+func GetSharedLibrary(namespace string) []string {
+	return DefaultRepository().GetSharedLibrary(namespace)
+}
+
+func GetTypelibPath(namespace string) string {
+	return DefaultRepository().GetTypelibPath(namespace)
+}
+
+func GetCPrefix(namespace string) string {
+	return DefaultRepository().GetCPrefix(namespace)
+}
+
+// Call invokes a function by name using the default repository.
+//
+//	gi.Call("Gtk", "Window", "set_title", win, "hello")
+func Call(namespace, container, name string, args ...interface{}) ([]interface{}, error) {
+	return DefaultRepository().Call(namespace, container, name, args...)
+}
@@ -0,0 +1,3 @@
+module github.com/dradtke/go-gi
+
+go 1.21
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+var update = flag.Bool("update", false, "replace the golden output in testdata/golden with what's generated now")
+
+var (
+	testdataDir = filepath.Join("testdata", "gotest")
+	goldenDir   = filepath.Join("testdata", "golden")
+)
+
+// TestGolden generates bindings for the small GoTest library in
+// testdata/gotest from its .gir file and compares them against the golden
+// output in testdata/golden, so changes to readParams or the snippets show
+// up as a diff, as do changes to what gets skipped, through the coverage
+// report kept with it. Generation uses the overrides in testdata/overrides.
+// After an intended change, run
+//
+//	go test -run TestGolden -update
+//
+// and commit the new golden files along with it. If the GObject
+// development files are installed, the library is built and the generated
+// packages are built and vetted against it, with and without build tags.
+func TestGolden(t *testing.T) {
+	out := generateGolden(t, model.GIRLoader{Path: []string{testdataDir}})
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		files := readTree(t, out)
+		for name, data := range files {
+			if _, err := WriteFile(goldenDir, name, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Logf("golden output updated")
+		return
+	}
+	compareTrees(t, readTree(t, goldenDir), readTree(t, out), nil)
+
+	t.Run("build", func(t *testing.T) {
+		lib := buildGoTest(t)
+		for _, tags := range []string{"", "gotest_1_2", "gobject_2_10", "gotest_1_2,gobject_2_10"} {
+			for _, cmd := range []string{"build", "vet"} {
+				goCommand(t, out, lib, cmd, "-tags="+tags, "./...")
+			}
+		}
+	})
+}
+
+// generateGolden generates bindings for GoTest, read by loader from
+// testdata/gotest, into a temporary directory, along with a go.mod and a coverage report, the way
+// "go-gi -overrides testdata/overrides -coverage coverage.txt" does.
+func generateGolden(t *testing.T, loader model.Loader) string {
+	t.Helper()
+	namespaces, err := model.LoadAll(loader, "GoTest", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(t, namespaces)
+	g.Overrides = os.DirFS(filepath.Join("testdata", "overrides"))
+	files, report, err := g.Generate(namespaces.DependencyOrder("GoTest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := t.TempDir()
+	if err := WriteGoMod(out, g.Module); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if _, err := WriteFile(out, name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := report.WriteFile(filepath.Join(out, "coverage.txt")); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// TestGoldenJSON generates the golden output from the model of GoTest
+// dumped to JSON in testdata/gotest, so that the JSON frontend has to agree
+// with the .gir one. After changing the .gir files, dump them again with
+//
+//	go-gi dump -gir testdata/gotest -deps -o testdata/gotest GoTest-1.0
+func TestGoldenJSON(t *testing.T) {
+	out := generateGolden(t, model.JSONLoader{Path: []string{testdataDir}})
+	compareTrees(t, readTree(t, goldenDir), readTree(t, out), nil)
+}
+
+// readTree returns the contents of every file under dir, by slash-separated
+// path relative to it.
+func readTree(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// compareTrees reports every file that golden and got don't agree on,
+// after passing the contents of both through trim if it isn't nil.
+func compareTrees(t *testing.T, golden, got map[string][]byte, trim func([]byte) []byte) {
+	t.Helper()
+	names := make([]string, 0, len(golden))
+	for name := range golden {
+		names = append(names, name)
+	}
+	for name := range got {
+		if _, ok := golden[name]; !ok {
+			t.Errorf("%s is generated but isn't in the golden output", name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, ok := got[name]
+		if !ok {
+			t.Errorf("%s is in the golden output but isn't generated", name)
+			continue
+		}
+		want := golden[name]
+		if trim != nil {
+			want, data = trim(want), trim(data)
+		}
+		if line, wantLine, gotLine, differ := firstDifference(want, data); differ {
+			t.Errorf("%s differs from the golden output at line %d:\n\twant: %s\n\tgot:  %s", name, line, wantLine, gotLine)
+		}
+	}
+}
+
+// firstDifference returns the number of the first line that a and b differ
+// on, along with that line of each.
+func firstDifference(a, b []byte) (int, string, string, bool) {
+	if bytes.Equal(a, b) {
+		return 0, "", "", false
+	}
+	as, bs := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+	for i := 0; ; i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x != y || i >= len(as) || i >= len(bs) {
+			return i + 1, x, y, true
+		}
+	}
+}
+
+// buildGoTest builds libgotest.so into a temporary directory and returns
+// it, skipping the test if the GObject development files aren't installed.
+func buildGoTest(t *testing.T) string {
+	t.Helper()
+	flags, err := exec.Command("pkg-config", "--cflags", "--libs", "gobject-2.0").Output()
+	if err != nil {
+		t.Skip("gobject-2.0 not found")
+	}
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "cc"
+	}
+	dir := t.TempDir()
+	args := append([]string{"-shared", "-fPIC", "-o", filepath.Join(dir, "libgotest.so"), filepath.Join(testdataDir, "gotest.c")}, strings.Fields(string(flags))...)
+	if out, err := exec.Command(cc, args...).CombinedOutput(); err != nil {
+		t.Fatalf("building libgotest.so: %v\n%s", err, out)
+	}
+	return dir
+}
+
+// goCommand runs a go command on the module generated in dir, compiling
+// against the GoTest headers and the library built into lib.
+func goCommand(t *testing.T, dir, lib string, args ...string) {
+	t.Helper()
+	include, err := filepath.Abs(testdataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := goCmd(dir, args...)
+	cmd.Env = append(cmd.Env, "CGO_CFLAGS=-I"+include, "CGO_LDFLAGS=-L"+lib)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// goCmd returns a go command to run on the module generated in dir, which
+// isn't part of any workspace.
+func goCmd(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=1", "GOFLAGS=", "GOWORK=off")
+	return cmd
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the language version declared by generated go.mod files, which has to
+// be at least 1.17 for their //go:build lines
+const goVersion = "1.17"
+
+// WriteGoMod makes dir the root of a module with the given path. An existing
+// go.mod is left alone as long as it declares the same module, so any
+// requirements added to it by hand survive regeneration.
+func WriteGoMod(dir, module string) error {
+	file := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(file)
+	if err == nil {
+		existing := ModulePath(data)
+		if existing != module {
+			return fmt.Errorf("%s declares module %q, not %q", file, existing, module)
+		}
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "module %s\n\ngo %s\n", module, goVersion)
+	return os.WriteFile(file, b.Bytes(), 0644)
+}
+
+// ModulePath returns the module path declared in the contents of a go.mod
+// file, or "" if there isn't one.
+func ModulePath(gomod []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(gomod))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
@@ -1,146 +1,248 @@
 package main
 
-/*
-#cgo pkg-config: glib-2.0 gobject-2.0
-#include <glib.h>
-#include <glib-object.h>
-
-gboolean check_version(gint major, gint minor) {
-	return GLIB_CHECK_VERSION(major, minor, 0);
-}
-*/
-import "C"
 import (
-	"bytes"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
+
+	"github.com/dradtke/go-gi/gi"
+	"github.com/dradtke/go-gi/model"
+)
+
+var (
+	outputDir    = flag.String("o", "gi", "directory to write the generated packages to")
+	snippetDir   = flag.String("snippets", "", "directory of code snippets (default: built in)")
+	templateDir  = flag.String("templates", "", "directory of per-namespace package headers (default: built in)")
+	blacklistDir = flag.String("blacklist", "", "directory of per-namespace symbol blacklists (default: built in)")
+	overrideDir  = flag.String("overrides", "", "directory of per-namespace overrides (default: built in)")
+	modulePath   = flag.String("module", "gi", "module path of the generated packages")
+	writeGoMod   = flag.Bool("gomod", true, "write a go.mod for the module to the output directory")
+	girPath      = flag.String("gir", "", "read .gir files from this list of directories instead of installed typelibs")
+	docsPath     = flag.String("docs", "", "list of directories of .gir files to take documentation from, for namespaces read from typelibs or models")
+	jsonPath     = flag.String("model", "", "read models written by \"go-gi dump\" from this list of directories instead of installed typelibs")
+	typelibPath  = flag.String("typelibdir", "", "list of directories to search for typelibs before the default ones")
+	libraryPath  = flag.String("libdir", "", "list of directories to search for the libraries typelibs refer to")
+	leakCheck    = flag.Bool("leakcheck", false, "fail if any introspection info is still referenced once generation is done")
+	deprecated   = flag.Bool("deprecated", false, "also generate deprecated symbols, marked with Deprecated: comments")
+	initialisms  = flag.String("initialisms", "", "comma-separated words to spell this way in Go names, on top of the usual ones, i.e. \"XPad,YPad\"")
+	workers      = flag.Int("j", runtime.NumCPU(), "number of types to render at once")
+	coverageFile = flag.String("coverage", "", "write a report of what was and wasn't bound to this file, as JSON if it ends in .json")
+	baselineFile = flag.String("baseline", "", "fail if anything bound according to this JSON coverage report no longer is")
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("usage: go run main.go <namespace>")
+	if len(os.Args) > 1 && os.Args[1] == "dump" {
+		dump(os.Args[2:])
 		return
 	}
 
-	// don't do this for GLib 2.36 and higher
-	if C.check_version(C.gint(2), C.gint(36)) == 0 {
-		C.g_type_init()
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-gi [flags] <namespace>[-<version>] | <file.gir> | <file.typelib> | <file.json>")
+		fmt.Fprintln(os.Stderr, "       go-gi dump [flags] <namespace>[-<version>] | <file.gir> | <file.typelib>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	namespace := os.Args[1]
-	ns := strings.ToLower(namespace)
+	// read it first, so that a bad path doesn't waste a whole run
+	var baseline *Coverage
+	if *baselineFile != "" {
+		var err error
+		if baseline, err = ReadCoverage(*baselineFile); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
 
-	fmt.Println("[*] Generating " + namespace + " bindings...")
-	// TODO: support specifying the namespace version
-	typelib, err := LoadNamespace(namespace, "")
+	prependSearchPaths()
+	fmt.Println("[*] Loading " + flag.Arg(0) + "...")
+	namespaces, namespace, err := loadNamespaces(flag.Arg(0))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		return
+		log.Fatal(err.Error())
 	}
-	defer FreeTypelib(typelib)
-
-	srcRel := filepath.Join("src", "github.com", "dradtke", "go-gi")
-	giSnippetsRel := filepath.Join(srcRel, "snippets")
-	giTemplatesRel := filepath.Join(srcRel, "templates")
-	giBlacklistRel := filepath.Join(srcRel, "blacklist")
-	outputDirRel := filepath.Join("src", "gi", ns)
-	var giSnippets, giTemplates, giBlacklist, outputDir string
-
-	// find a) the templates directory, and b) a place to put output files
-	gopath := strings.Split(os.Getenv("GOPATH"), string(os.PathListSeparator))
-	for _, dir := range gopath {
-		if giSnippets == "" {
-			f := filepath.Join(dir, giSnippetsRel)
-			if _, err := os.Stat(f); !os.IsNotExist(err) {
-				giSnippets = f
-			}
-		}
-		if giTemplates == "" {
-			f := filepath.Join(dir, giTemplatesRel)
-			if _, err := os.Stat(f); !os.IsNotExist(err) {
-				giTemplates = f
-			}
-		}
-		if giBlacklist == "" {
-			f := filepath.Join(dir, giBlacklistRel)
-			if _, err := os.Stat(f); !os.IsNotExist(err) {
-				giBlacklist = f
-			}
-		}
-		if outputDir == "" {
-			f := filepath.Join(dir, outputDirRel)
-			if err := os.MkdirAll(f, 0755); err != nil {
-				fmt.Println(err.Error())
-			} else {
-				outputDir = f
-			}
-		}
+
+	giSnippets := Assets(*snippetDir, "snippets")
+	giTemplates := Assets(*templateDir, "templates")
+	giBlacklist := Assets(*blacklistDir, "blacklist")
+	giOverrides := Assets(*overrideDir, "overrides")
+
+	tmpl, err := template.New("go-gi").ParseFS(giSnippets, "*")
+	if err != nil {
+		log.Fatal(err.Error())
 	}
-	if giSnippets == "" {
-		log.Fatal("snippet folder not found")
+
+	if *writeGoMod {
+		if err := WriteGoMod(*outputDir, *modulePath); err != nil {
+			log.Fatal(err.Error())
+		}
 	}
-	if giTemplates == "" {
-		log.Fatal("template folder not found")
+
+	order := namespaces.DependencyOrder(namespace)
+	g := &Generator{
+		Namespaces: namespaces,
+		Snippets:   tmpl,
+		Templates:  giTemplates,
+		Blacklist:  giBlacklist,
+		Overrides:  giOverrides,
+		Module:     *modulePath,
+		Workers:    *workers,
+		Deprecated: *deprecated,
 	}
-	if outputDir == "" {
-		log.Fatal("no writable output directory found")
+	if *initialisms != "" {
+		g.Initialisms = strings.Split(*initialisms, ",")
 	}
-
-	startingPoint, err := os.Open(filepath.Join(giTemplates, ns + ".go"))
+	fmt.Println("[*] Generating " + strings.Join(order, ", ") + " bindings...")
+	start := time.Now()
+	files, report, err := g.Generate(order)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return
+		log.Fatal(err.Error())
 	}
+	fmt.Printf("[*] Generated %d namespaces in %s with %d workers\n", len(order), time.Since(start).Round(time.Millisecond), *workers)
 
-	header, err := ioutil.ReadAll(startingPoint)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		filename, err := WriteFile(*outputDir, name, files[name])
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Println("[*] Bindings written to " + filename)
 	}
 
-	blacklist := make(map[string] bool)
-	if f, err := os.Open(filepath.Join(giBlacklist, ns)); err == nil {
-		if data, err := ioutil.ReadAll(f); err == nil {
-			lines := strings.Split(string(data), "\n")
-			for _, line := range lines {
-				if len(line) == 0 || line[0:1] == "#" {
-					continue
-				}
-				blacklist[line] = true
+	for _, line := range strings.Split(strings.TrimSpace(report.Summary()), "\n") {
+		fmt.Println("[*] Coverage of " + line)
+	}
+	if *coverageFile != "" {
+		if err := report.WriteFile(*coverageFile); err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Println("[*] Coverage report written to " + *coverageFile)
+	}
+	if baseline != nil {
+		lost := report.Regressions(baseline)
+		for _, sym := range lost {
+			line := "[!] No longer bound: " + sym.Kind + " " + sym.Name + ": " + sym.Reason
+			if sym.Detail != "" {
+				line += " (" + sym.Detail + ")"
 			}
+			fmt.Fprintln(os.Stderr, line)
 		}
+		if len(lost) > 0 {
+			log.Fatalf("%d symbols bound in %s no longer are", len(lost), *baselineFile)
+		}
+		fmt.Println("[*] Nothing bound in " + *baselineFile + " was lost.")
 	}
 
-	var code bytes.Buffer
-	code.Write(header)
+	fmt.Println("[*] Run \"go build " + path.Join(*modulePath, strings.ToLower(namespace)) + "\" from " + *outputDir + " to compile them.")
 
-	tmpl := template.Must(template.New("go-gi").ParseGlob(filepath.Join(giSnippets, "*")))
+	if *leakCheck {
+		if n := outstandingInfos(); n > 0 {
+			log.Fatalf("%d introspection infos were never freed", n)
+		}
+		fmt.Println("[*] No introspection infos leaked.")
+	}
+}
 
-	// used to prevent duplicate methods, interfaces, etc.
-	exists := make(map[string] bool)
+// outstandingInfos collects garbage until every unreachable info has been
+// finalized, then returns the number of infos that are left.
+func outstandingInfos() int {
+	for i := 0; i < 10 && gi.LiveInfos() > 0; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return gi.LiveInfos()
+}
 
-	n := GetNumInfos(namespace)
-	for i := 0; i < n; i++ {
-		info := GetInfo(namespace, i)
-		switch info.Type {
-			case Enum: ProcessEnum(info, &code, tmpl, &blacklist)
-			case Object: ProcessObject(info, &code, tmpl, &exists, &blacklist)
+// prependSearchPaths adds the directories given with -typelibdir and
+// -libdir to those libgirepository searches.
+func prependSearchPaths() {
+	// prepend in reverse so that the first directory given is searched first
+	dirs := filepath.SplitList(*typelibPath)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		gi.PrependSearchPath(dirs[i])
+	}
+	dirs = filepath.SplitList(*libraryPath)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		gi.PrependLibraryPath(dirs[i])
+	}
+}
+
+// loadNamespaces loads the namespace named on the command line along with
+// everything it depends on, returning them and the name of the namespace.
+func loadNamespaces(arg string) (model.Namespaces, string, error) {
+	var loader model.Loader = TypelibLoader{}
+	if *girPath != "" {
+		loader = model.GIRLoader{Path:filepath.SplitList(*girPath)}
+	} else if *jsonPath != "" {
+		loader = model.JSONLoader{Path:filepath.SplitList(*jsonPath)}
+	}
+
+	var namespace, version string
+	if strings.HasSuffix(arg, ".gir") {
+		// read the file directly, looking for its dependencies next to it
+		root, err := model.ReadGIR(arg)
+		if err != nil {
+			return nil, "", err
+		}
+		namespace, version = root.Name, root.Version
+		path := append([]string{filepath.Dir(arg)}, filepath.SplitList(*girPath)...)
+		loader = model.GIRLoader{Path:append(path, model.DefaultGIRPath...)}
+	} else if strings.HasSuffix(arg, ".json") {
+		root, err := model.ReadJSON(arg)
+		if err != nil {
+			return nil, "", err
+		}
+		namespace, version = root.Name, root.Version
+		path := append([]string{filepath.Dir(arg)}, filepath.SplitList(*jsonPath)...)
+		loader = model.JSONLoader{Path:path}
+	} else if strings.HasSuffix(arg, ".typelib") {
+		// dependencies are most likely built alongside it
+		gi.PrependSearchPath(filepath.Dir(arg))
+		ns, err := gi.LoadTypelibFile(arg)
+		if err != nil {
+			return nil, "", err
 		}
-		info.Free()
+		namespace = ns
+	} else {
+		namespace, version = model.SplitNamespace(arg)
 	}
 
-	file, err := os.Create(filepath.Join(outputDir, ns + ".go"))
+	namespaces, err := model.LoadAll(loader, namespace, version)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, "", err
 	}
-
-	code.WriteTo(file)
-	file.Close()
-	fmt.Println("[*] Bindings written to " + file.Name())
-	fmt.Println("[*] Run \"go build gi/" + ns + "\" to compile them.")
+	if *docsPath != "" {
+		docs := model.GIRLoader{Path:filepath.SplitList(*docsPath)}
+		girs := make(model.Namespaces)
+		for _, ns := range namespaces {
+			gir, err := docs.Load(ns.Name, ns.Version)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[!] No documentation for " + ns.Name + ", so none of it will be gated behind build tags: " + err.Error())
+				continue
+			}
+			girs[ns.Name] = gir
+		}
+		// so that their types match those of the typelibs
+		girs.ResolveAliases()
+		for name, gir := range girs {
+			model.CopyDocs(namespaces[name], gir)
+		}
+	} else if _, ok := loader.(TypelibLoader); ok {
+		// typelibs don't record when anything was added
+		fmt.Fprintln(os.Stderr, "[!] Typelibs don't say which version anything was added in, so nothing will be gated behind build tags; use -gir, or -docs to take that from .gir files")
+	}
+	return namespaces, namespace, nil
 }
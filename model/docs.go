@@ -0,0 +1,146 @@
+package model
+
+// CopyDocs fills in the documentation of dst from src, which describes the
+// same namespace. Typelibs don't keep any documentation, the versions
+// things were added and deprecated in, C type names, headers or pkg-config
+// packages, so this is how a namespace loaded from one gets them from its
+// .gir file. Things are matched up by name, and anything only one of them
+// has is left alone.
+func CopyDocs(dst, src *Namespace) {
+	if len(dst.CIncludes) == 0 {
+		dst.CIncludes = src.CIncludes
+	}
+	if len(dst.Packages) == 0 {
+		dst.Packages = src.Packages
+	}
+
+	enums := make(map[string]*Enumeration)
+	for _, enum := range src.Enums {
+		enums[enum.Name] = enum
+	}
+	for _, enum := range dst.Enums {
+		from, ok := enums[enum.Name]
+		if !ok {
+			continue
+		}
+		enum.Doc = from.Doc
+		copyVersions(&enum.Version, &enum.DeprecatedVersion, &enum.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+		members := make(map[string]*Member)
+		for _, m := range from.Values {
+			members[m.Name] = m
+		}
+		for _, m := range enum.Values {
+			if from, ok := members[m.Name]; ok {
+				m.Doc = from.Doc
+			}
+		}
+	}
+
+	classes := make(map[string]*Class)
+	for _, obj := range src.Classes {
+		classes[obj.Name] = obj
+	}
+	for _, obj := range dst.Classes {
+		if from, ok := classes[obj.Name]; ok {
+			obj.Doc = from.Doc
+			copyVersions(&obj.Version, &obj.DeprecatedVersion, &obj.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+			copyPropertyDocs(obj.Properties, from.Properties)
+			copyCallableDocs(obj.Methods, from.Methods)
+			copyCallableDocs(obj.Signals, from.Signals)
+		}
+	}
+
+	ifaces := make(map[string]*Interface)
+	for _, iface := range src.Interfaces {
+		ifaces[iface.Name] = iface
+	}
+	for _, iface := range dst.Interfaces {
+		if from, ok := ifaces[iface.Name]; ok {
+			iface.Doc = from.Doc
+			copyVersions(&iface.Version, &iface.DeprecatedVersion, &iface.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+			copyPropertyDocs(iface.Properties, from.Properties)
+			copyCallableDocs(iface.Methods, from.Methods)
+			copyCallableDocs(iface.Signals, from.Signals)
+		}
+	}
+
+	constants := make(map[string]*Constant)
+	for _, constant := range src.Constants {
+		constants[constant.Name] = constant
+	}
+	for _, constant := range dst.Constants {
+		if from, ok := constants[constant.Name]; ok {
+			constant.Doc = from.Doc
+			if from.CIdentifier != "" {
+				// more reliable than working it out from the prefix
+				constant.CIdentifier = from.CIdentifier
+			}
+			copyVersions(&constant.Version, &constant.DeprecatedVersion, &constant.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+		}
+	}
+
+	copyCallableDocs(dst.Functions, src.Functions)
+}
+
+func copyPropertyDocs(dst, src []*Property) {
+	props := make(map[string]*Property)
+	for _, prop := range src {
+		props[prop.Name] = prop
+	}
+	for _, prop := range dst {
+		if from, ok := props[prop.Name]; ok {
+			prop.Doc = from.Doc
+			copyVersions(&prop.Version, &prop.DeprecatedVersion, &prop.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+		}
+	}
+}
+
+func copyCallableDocs(dst, src []*Callable) {
+	fns := make(map[string]*Callable)
+	for _, fn := range src {
+		fns[fn.Name] = fn
+	}
+	for _, fn := range dst {
+		from, ok := fns[fn.Name]
+		if !ok {
+			continue
+		}
+		fn.Doc = from.Doc
+		copyVersions(&fn.Version, &fn.DeprecatedVersion, &fn.DeprecatedDoc, from.Version, from.DeprecatedVersion, from.DeprecatedDoc)
+		fn.ReturnDoc = from.ReturnDoc
+		// typelibs don't know the C types either
+		if fn.InstanceCType == "" {
+			fn.InstanceCType = from.InstanceCType
+		}
+		copyCType(fn.Return, from.Return)
+		params := make(map[string]*Param)
+		for _, p := range from.Params {
+			params[p.Name] = p
+		}
+		for _, p := range fn.Params {
+			if from, ok := params[p.Name]; ok {
+				p.Doc = from.Doc
+				copyCType(p.Type, from.Type)
+			}
+		}
+	}
+}
+
+// copyCType fills in the C type of dst from src, if they're the same type.
+func copyCType(dst, src *TypeRef) {
+	if dst != nil && src != nil && dst.CType == "" && dst.Tag == src.Tag && dst.Interface == src.Interface {
+		dst.CType = src.CType
+	}
+}
+
+// copyVersions fills in when something was added and deprecated, keeping
+// versions that are already known, along with the deprecation doc.
+func copyVersions(version, deprecatedVersion, deprecatedDoc *string, fromVersion, fromDeprecatedVersion, fromDeprecatedDoc string) {
+	if *version == "" {
+		*version = fromVersion
+	}
+	if *deprecatedVersion == "" {
+		*deprecatedVersion = fromDeprecatedVersion
+	}
+	*deprecatedDoc = fromDeprecatedDoc
+}
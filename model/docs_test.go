@@ -0,0 +1,31 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCopyDocs(t *testing.T) {
+	src := &Namespace{
+		Name:      "Foo",
+		CIncludes: []string{"foo.h"},
+		Packages:  []string{"foo-1.0"},
+		Functions: []*Callable{{
+			Name:    "bar",
+			Doc:     "Bars.",
+			Version: "1.2",
+			Return:  &TypeRef{Tag: Int32Tag, CType: "gint"},
+		}},
+	}
+	dst := &Namespace{
+		Name:      "Foo",
+		Functions: []*Callable{{Name: "bar", Return: &TypeRef{Tag: Int32Tag}}},
+	}
+	CopyDocs(dst, src)
+	if !reflect.DeepEqual(dst.CIncludes, src.CIncludes) || !reflect.DeepEqual(dst.Packages, src.Packages) {
+		t.Errorf("headers %v and packages %v, want %v and %v", dst.CIncludes, dst.Packages, src.CIncludes, src.Packages)
+	}
+	if fn := dst.Functions[0]; fn.Doc != "Bars." || fn.Version != "1.2" || fn.Return.CType != "gint" {
+		t.Errorf("bar has doc %q, version %q and C type %q", fn.Doc, fn.Version, fn.Return.CType)
+	}
+}
@@ -0,0 +1,599 @@
+package model
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GIRLoader reads namespaces from .gir files found in a list of
+// directories, so bindings can be generated without the typelibs being
+// installed.
+type GIRLoader struct {
+	Path []string
+}
+
+// DefaultGIRPath is where distributions install .gir files.
+var DefaultGIRPath = []string{"/usr/share/gir-1.0"}
+
+func (loader GIRLoader) Load(namespace, version string) (*Namespace, error) {
+	file := loader.Find(namespace, version)
+	if file == "" {
+		if version == "" {
+			return nil, fmt.Errorf("no .gir file found for %s", namespace)
+		}
+		return nil, fmt.Errorf("no .gir file found for %s-%s", namespace, version)
+	}
+	return ReadGIR(file)
+}
+
+// Find returns the path to the .gir file for a namespace, or "" if there
+// isn't one. With no version, the highest one found is used.
+func (loader GIRLoader) Find(namespace, version string) string {
+	return findFile(loader.Path, namespace, version, ".gir")
+}
+
+// findFile looks through a list of directories for a file named after a
+// namespace, i.e. "Gtk-3.0.gir". With no version, the highest one found is
+// used.
+func findFile(path []string, namespace, version, ext string) string {
+	for _, dir := range path {
+		if version != "" {
+			f := filepath.Join(dir, namespace+"-"+version+ext)
+			if _, err := os.Stat(f); err == nil {
+				return f
+			}
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, namespace+"-*"+ext))
+		latest, latestVersion := "", ""
+		for _, f := range matches {
+			v := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(f), namespace+"-"), ext)
+			if latest == "" || CompareVersions(v, latestVersion) > 0 {
+				latest, latestVersion = f, v
+			}
+		}
+		if latest != "" {
+			return latest
+		}
+	}
+	return ""
+}
+
+// ReadGIR parses a single .gir file.
+func ReadGIR(filename string) (*Namespace, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var repo girRepository
+	if err := xml.NewDecoder(f).Decode(&repo); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	if repo.Namespace.Name == "" {
+		return nil, errors.New(filename + ": no namespace defined")
+	}
+	ns := repo.toNamespace()
+	// aliases from other namespaces are resolved by LoadAll
+	Namespaces{ns.Name: ns}.ResolveAliases()
+	return ns, nil
+}
+
+/* -- XML structure -- */
+
+// <include> and <c:include> only differ by their XML namespace.
+type girRepository struct {
+	Includes  []girInclude `xml:"http://www.gtk.org/introspection/core/1.0 include"`
+	CIncludes []girInclude `xml:"http://www.gtk.org/introspection/c/1.0 include"`
+	Packages  []girInclude `xml:"package"`
+	Namespace girNamespace `xml:"namespace"`
+}
+
+// girInclude is used for <include>, <c:include> and <package> elements.
+type girInclude struct {
+	Name    string `xml:"name,attr"`
+	Version string `xml:"version,attr"`
+}
+
+type girNamespace struct {
+	Name         string        `xml:"name,attr"`
+	Version      string        `xml:"version,attr"`
+	CPrefixes    string        `xml:"identifier-prefixes,attr"`
+	Libraries    string        `xml:"shared-library,attr"`
+	Classes      []girClass    `xml:"class"`
+	Interfaces   []girClass    `xml:"interface"`
+	Enumerations []girEnum     `xml:"enumeration"`
+	Bitfields    []girEnum     `xml:"bitfield"`
+	Constants    []girConstant `xml:"constant"`
+	Functions    []girFunction `xml:"function"`
+	Aliases      []girAlias    `xml:"alias"`
+}
+
+type girInfo struct {
+	Name              string `xml:"name,attr"`
+	Introspectable    string `xml:"introspectable,attr"`
+	Version           string `xml:"version,attr"`
+	Deprecated        string `xml:"deprecated,attr"`
+	DeprecatedVersion string `xml:"deprecated-version,attr"`
+	Doc               string `xml:"doc"`
+	DocDeprecated     string `xml:"doc-deprecated"`
+}
+
+// girClass is used for both <class> and <interface> elements.
+type girClass struct {
+	girInfo
+	CType         string        `xml:"type,attr"`
+	Parent        string        `xml:"parent,attr"`
+	Abstract      string        `xml:"abstract,attr"`
+	Fundamental   string        `xml:"fundamental,attr"`
+	Implements    []girInclude  `xml:"implements"`
+	Prerequisites []girInclude  `xml:"prerequisite"`
+	Constructors  []girFunction `xml:"constructor"`
+	Methods       []girFunction `xml:"method"`
+	Functions     []girFunction `xml:"function"`
+	Properties    []girProperty `xml:"property"`
+	Signals       []girFunction `xml:"http://www.gtk.org/introspection/glib/1.0 signal"`
+}
+
+type girProperty struct {
+	girReturn
+	girInfo
+	Readable      string `xml:"readable,attr"`
+	Writable      string `xml:"writable,attr"`
+	Construct     string `xml:"construct,attr"`
+	ConstructOnly string `xml:"construct-only,attr"`
+}
+
+type girConstant struct {
+	girReturn
+	girInfo
+	Value       string `xml:"value,attr"`
+	CIdentifier string `xml:"identifier,attr"`
+	CType       string `xml:"type,attr"` // what older scanners put the identifier in
+}
+
+// identifier returns the C name of the constant.
+func (c girConstant) identifier() string {
+	if c.CIdentifier != "" {
+		return c.CIdentifier
+	}
+	return c.CType
+}
+
+// girEnum is used for both <enumeration> and <bitfield> elements.
+type girEnum struct {
+	girInfo
+	CType   string      `xml:"type,attr"`
+	Members []girMember `xml:"member"`
+}
+
+type girMember struct {
+	Name        string `xml:"name,attr"`
+	Value       string `xml:"value,attr"`
+	CIdentifier string `xml:"identifier,attr"`
+	Doc         string `xml:"doc"`
+}
+
+// toEnum converts an <enumeration>, or a <bitfield> if flags is set.
+func (e girEnum) toEnum(flags bool) *Enumeration {
+	enum := &Enumeration{
+		Name:              e.Name,
+		CType:             e.CType,
+		Flags:             flags,
+		Doc:               e.Doc,
+		Version:           e.Version,
+		Deprecated:        e.deprecated(),
+		DeprecatedVersion: e.DeprecatedVersion,
+		DeprecatedDoc:     e.deprecatedDoc(),
+	}
+	for _, m := range e.Members {
+		value, _ := strconv.ParseInt(m.Value, 10, 64)
+		enum.Values = append(enum.Values, &Member{
+			Name:        m.Name,
+			CIdentifier: m.CIdentifier,
+			Doc:         m.Doc,
+			Value:       value,
+		})
+	}
+	return enum
+}
+
+type girAlias struct {
+	girReturn
+	Name string `xml:"name,attr"`
+}
+
+type girFunction struct {
+	girInfo
+	CIdentifier string         `xml:"identifier,attr"`
+	Throws      string         `xml:"throws,attr"`
+	Return      girReturnValue `xml:"return-value"`
+	Instance    *girParam      `xml:"parameters>instance-parameter"`
+	Params      []girParam     `xml:"parameters>parameter"`
+}
+
+type girReturn struct {
+	Transfer  string   `xml:"transfer-ownership,attr"`
+	Nullable  string   `xml:"nullable,attr"`
+	AllowNone string   `xml:"allow-none,attr"`
+	Type      *girType `xml:"type"`
+	Array     *girType `xml:"array"`
+}
+
+// girReturnValue is a <return-value>, which unlike the other users of
+// girReturn has a <doc> of its own.
+type girReturnValue struct {
+	girReturn
+	Doc string `xml:"doc"`
+}
+
+type girParam struct {
+	girReturn
+	Name            string    `xml:"name,attr"`
+	Direction       string    `xml:"direction,attr"`
+	Optional        string    `xml:"optional,attr"`
+	CallerAllocates string    `xml:"caller-allocates,attr"`
+	Skip            string    `xml:"skip,attr"`
+	Doc             string    `xml:"doc"`
+	Varargs         *struct{} `xml:"varargs"`
+}
+
+// girType is used for both <type> and <array> elements.
+type girType struct {
+	Name  string `xml:"name,attr"`
+	CType string `xml:"type,attr"`
+}
+
+/* -- Conversion to the model -- */
+
+func girBool(attr string) bool {
+	return attr == "1"
+}
+
+func (info girInfo) deprecated() bool {
+	return info.Deprecated != "" && info.Deprecated != "0"
+}
+
+func (info girInfo) deprecatedDoc() string {
+	if info.DocDeprecated == "" && info.deprecated() && info.Deprecated != "1" {
+		// older files give the reason here instead of in <doc-deprecated>
+		return info.Deprecated
+	}
+	return info.DocDeprecated
+}
+
+func (info girInfo) skip() bool {
+	// the typelib compiler leaves these out, so do the same
+	return info.Introspectable == "0"
+}
+
+// unintrospectable returns why the typelib compiler would leave out f, or
+// "" if it wouldn't.
+func (f girFunction) unintrospectable() string {
+	for _, p := range f.Params {
+		if p.Varargs != nil {
+			return "takes varargs"
+		}
+	}
+	if f.skip() {
+		return "marked not introspectable"
+	}
+	return ""
+}
+
+func (repo *girRepository) toNamespace() *Namespace {
+	gir := repo.Namespace
+	ns := &Namespace{
+		Name:    gir.Name,
+		Version: gir.Version,
+		// the first prefix is the one that libgirepository reports
+		CPrefix: strings.Split(gir.CPrefixes, ",")[0],
+	}
+	for _, include := range repo.Includes {
+		ns.Includes = append(ns.Includes, include.Name+"-"+include.Version)
+	}
+	for _, include := range repo.CIncludes {
+		ns.CIncludes = append(ns.CIncludes, include.Name)
+	}
+	for _, pkg := range repo.Packages {
+		ns.Packages = append(ns.Packages, pkg.Name)
+	}
+	if gir.Libraries != "" {
+		ns.SharedLibraries = strings.Split(gir.Libraries, ",")
+	}
+
+	for _, a := range gir.Aliases {
+		if ns.Aliases == nil {
+			ns.Aliases = make(map[string]*TypeRef)
+		}
+		ns.Aliases[a.Name] = a.toType(gir.Name)
+	}
+
+	for _, e := range gir.Enumerations {
+		if !e.skip() {
+			ns.Enums = append(ns.Enums, e.toEnum(false))
+		}
+	}
+	for _, e := range gir.Bitfields {
+		if !e.skip() {
+			ns.Enums = append(ns.Enums, e.toEnum(true))
+		}
+	}
+
+	for _, c := range gir.Classes {
+		if c.skip() {
+			continue
+		}
+		obj := &Class{
+			Name:              c.Name,
+			Namespace:         gir.Name,
+			CType:             c.CType,
+			Doc:               c.Doc,
+			Abstract:          girBool(c.Abstract),
+			Fundamental:       girBool(c.Fundamental),
+			Version:           c.Version,
+			Deprecated:        c.deprecated(),
+			DeprecatedVersion: c.DeprecatedVersion,
+			DeprecatedDoc:     c.deprecatedDoc(),
+		}
+		if c.Parent != "" && !obj.Fundamental {
+			obj.Parent = qualify(gir.Name, c.Parent)
+		}
+		for _, iface := range c.Implements {
+			obj.Interfaces = append(obj.Interfaces, qualify(gir.Name, iface.Name))
+		}
+		obj.Properties = c.properties(gir.Name)
+		obj.Methods = c.methods(gir.Name)
+		obj.Signals = c.signals(gir.Name)
+		ns.Classes = append(ns.Classes, obj)
+	}
+
+	for _, c := range gir.Interfaces {
+		if c.skip() {
+			continue
+		}
+		iface := &Interface{
+			Name:              c.Name,
+			Namespace:         gir.Name,
+			CType:             c.CType,
+			Doc:               c.Doc,
+			Version:           c.Version,
+			Deprecated:        c.deprecated(),
+			DeprecatedVersion: c.DeprecatedVersion,
+			DeprecatedDoc:     c.deprecatedDoc(),
+		}
+		for _, prereq := range c.Prerequisites {
+			iface.Prerequisites = append(iface.Prerequisites, qualify(gir.Name, prereq.Name))
+		}
+		iface.Properties = c.properties(gir.Name)
+		iface.Methods = c.methods(gir.Name)
+		iface.Signals = c.signals(gir.Name)
+		ns.Interfaces = append(ns.Interfaces, iface)
+	}
+
+	for _, f := range gir.Functions {
+		ns.Functions = append(ns.Functions, f.toFunction(gir.Name, FunctionFlags{}))
+	}
+
+	for _, c := range gir.Constants {
+		if c.skip() {
+			continue
+		}
+		ns.Constants = append(ns.Constants, &Constant{
+			Name:              c.Name,
+			CIdentifier:       c.identifier(),
+			Doc:               c.Doc,
+			Version:           c.Version,
+			Deprecated:        c.deprecated(),
+			DeprecatedVersion: c.DeprecatedVersion,
+			DeprecatedDoc:     c.deprecatedDoc(),
+			Type:              c.toType(gir.Name),
+			Value:             c.Value,
+		})
+	}
+
+	return ns
+}
+
+func (c girClass) methods(namespace string) []*Callable {
+	var methods []*Callable
+	for _, f := range c.Constructors {
+		methods = append(methods, f.toFunction(namespace, FunctionFlags{IsConstructor: true}))
+	}
+	for _, f := range c.Methods {
+		methods = append(methods, f.toFunction(namespace, FunctionFlags{IsMethod: true}))
+	}
+	for _, f := range c.Functions {
+		methods = append(methods, f.toFunction(namespace, FunctionFlags{}))
+	}
+	return methods
+}
+
+func (c girClass) signals(namespace string) []*Callable {
+	var signals []*Callable
+	for _, f := range c.Signals {
+		signals = append(signals, f.toFunction(namespace, FunctionFlags{}))
+	}
+	return signals
+}
+
+func (c girClass) properties(namespace string) []*Property {
+	var props []*Property
+	for _, p := range c.Properties {
+		if p.skip() {
+			continue
+		}
+		props = append(props, &Property{
+			Name:              p.Name,
+			Doc:               p.Doc,
+			Version:           p.Version,
+			Deprecated:        p.deprecated(),
+			DeprecatedVersion: p.DeprecatedVersion,
+			DeprecatedDoc:     p.deprecatedDoc(),
+			Type:              p.toType(namespace),
+			Transfer:          girTransfer(p.Transfer),
+			// properties are readable unless they say otherwise
+			Readable:      p.Readable != "0",
+			Writable:      girBool(p.Writable),
+			Construct:     girBool(p.Construct),
+			ConstructOnly: girBool(p.ConstructOnly),
+		})
+	}
+	return props
+}
+
+// qualify adds the namespace to a type name that doesn't have one.
+func qualify(namespace, name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return QualifiedName(namespace, name)
+}
+
+// toFunction converts a function, method or signal. Ones the typelib
+// compiler would leave out are kept, unlike other unintrospectable
+// things, so that they can be reported as skipped; Unintrospectable says
+// why they can't be bound.
+func (f girFunction) toFunction(namespace string, flags FunctionFlags) *Callable {
+	flags.Throws = girBool(f.Throws)
+	fn := &Callable{
+		Name:              f.Name,
+		Symbol:            f.CIdentifier,
+		Doc:               f.Doc,
+		Version:           f.Version,
+		Deprecated:        f.deprecated(),
+		DeprecatedVersion: f.DeprecatedVersion,
+		DeprecatedDoc:     f.deprecatedDoc(),
+		Flags:             flags,
+		Return:            f.Return.toType(namespace),
+		ReturnTransfer:    girTransfer(f.Return.Transfer),
+		ReturnDoc:         f.Return.Doc,
+		MayReturnNull:     girBool(f.Return.Nullable) || girBool(f.Return.AllowNone),
+		Unintrospectable:  f.unintrospectable(),
+	}
+	if f.Instance != nil && f.Instance.Type != nil {
+		fn.InstanceCType = f.Instance.Type.CType
+	}
+	for _, p := range f.Params {
+		if p.Varargs != nil {
+			break
+		}
+		dir := Direction(In)
+		switch p.Direction {
+		case "out":
+			dir = Out
+		case "inout":
+			dir = InOut
+		}
+		typ := p.toType(namespace)
+		if dir != In && !girBool(p.CallerAllocates) && typ.Tag != VoidTag && typ.CType != "" {
+			// the typelib describes what the out pointer points to
+			typ.Pointer = strings.Count(typ.CType, "*") > 1
+		}
+		fn.Params = append(fn.Params, &Param{
+			Name:            p.Name,
+			Doc:             p.Doc,
+			Direction:       dir,
+			Transfer:        girTransfer(p.Transfer),
+			Nullable:        girBool(p.Nullable) || girBool(p.AllowNone),
+			Optional:        girBool(p.Optional),
+			CallerAllocates: girBool(p.CallerAllocates),
+			Skip:            girBool(p.Skip),
+			Type:            typ,
+		})
+	}
+	return fn
+}
+
+func girTransfer(attr string) Transfer {
+	switch attr {
+	case "container":
+		return Container
+	case "full":
+		return Everything
+	}
+	return Nothing
+}
+
+// girTypeTags maps the names of basic types in .gir files to their tags.
+// glong and gulong are assumed to be 64 bits wide, just as the typelib
+// compiler would on the machines we generate on.
+var girTypeTags = map[string]TypeTag{
+	"none":           VoidTag,
+	"gpointer":       VoidTag,
+	"gconstpointer":  VoidTag,
+	"gboolean":       BooleanTag,
+	"gint8":          Int8Tag,
+	"gchar":          Int8Tag,
+	"guint8":         Uint8Tag,
+	"guchar":         Uint8Tag,
+	"gint16":         Int16Tag,
+	"gshort":         Int16Tag,
+	"guint16":        Uint16Tag,
+	"gushort":        Uint16Tag,
+	"gint32":         Int32Tag,
+	"gint":           Int32Tag,
+	"guint32":        Uint32Tag,
+	"guint":          Uint32Tag,
+	"gint64":         Int64Tag,
+	"glong":          Int64Tag,
+	"gssize":         Int64Tag,
+	"goffset":        Int64Tag,
+	"gintptr":        Int64Tag,
+	"guint64":        Uint64Tag,
+	"gulong":         Uint64Tag,
+	"gsize":          Uint64Tag,
+	"guintptr":       Uint64Tag,
+	"gfloat":         FloatTag,
+	"gdouble":        DoubleTag,
+	"GType":          GTypeTag,
+	"utf8":           Utf8Tag,
+	"filename":       FilenameTag,
+	"gunichar":       UnicharTag,
+	"GLib.List":      GListTag,
+	"GLib.SList":     GSListTag,
+	"GLib.HashTable": GHashTag,
+	"GLib.Error":     ErrorTag,
+}
+
+func (r girReturn) toType(namespace string) *TypeRef {
+	if r.Array != nil {
+		typ := &TypeRef{Tag: ArrayTag, CType: r.Array.CType, Pointer: true}
+		if r.Array.CType != "" {
+			typ.Pointer = strings.Contains(r.Array.CType, "*")
+		}
+		return typ
+	}
+	if r.Type == nil {
+		return &TypeRef{Tag: VoidTag}
+	}
+
+	name := r.Type.Name
+	typ := &TypeRef{CType: r.Type.CType}
+	if tag, ok := girTypeTags[name]; ok {
+		typ.Tag = tag
+	} else if tag, ok := girTypeTags["GLib."+name]; ok && namespace == "GLib" {
+		typ.Tag = tag
+	} else {
+		typ.Tag = InterfaceTag
+		typ.Interface = qualify(namespace, name)
+	}
+
+	switch {
+	case typ.Tag == VoidTag:
+		// gpointer hides its asterisk
+		typ.Pointer = name != "none"
+	case typ.CType != "":
+		typ.Pointer = strings.Contains(typ.CType, "*")
+	case typ.Tag == Utf8Tag, typ.Tag == FilenameTag, typ.Tag == GListTag, typ.Tag == GSListTag,
+		typ.Tag == GHashTag, typ.Tag == ErrorTag, typ.Tag == InterfaceTag:
+		typ.Pointer = true
+	}
+	return typ
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindFileLatest(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Foo-9.0.gir", "Foo-10.0.gir", "Foo-2.gir", "FooBar-11.0.gir"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := findFile([]string{dir}, "Foo", "", ".gir"), filepath.Join(dir, "Foo-10.0.gir"); got != want {
+		t.Errorf("latest Foo is %s, want %s", got, want)
+	}
+	if got, want := findFile([]string{dir}, "Foo", "9.0", ".gir"), filepath.Join(dir, "Foo-9.0.gir"); got != want {
+		t.Errorf("Foo 9.0 is %s, want %s", got, want)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.8", "3.10", -1},
+		{"10.0", "9.0", 1},
+		{"2.0", "2", 0},
+		{"1.2.3", "1.2", 1},
+	}
+	for _, test := range tests {
+		if got := CompareVersions(test.a, test.b); got != test.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	loader := GIRLoader{Path: []string{filepath.Join("..", "testdata", "gotest")}}
+	namespaces, err := LoadAll(loader, "GoTest", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	thing := namespaces.Class("GoTest.Thing")
+	if thing == nil {
+		t.Fatal("GoTest.Thing wasn't loaded")
+	}
+	for _, method := range thing.Methods {
+		if method.Name != "has_tag" {
+			continue
+		}
+		want := TypeRef{Tag: Uint32Tag, CType: "GQuark"}
+		if got := method.Params[0].Type; *got != want {
+			t.Errorf("GLib.Quark resolved to %+v, want %+v", *got, want)
+		}
+		return
+	}
+	t.Error("GoTest.Thing.has_tag wasn't loaded")
+}
+
+func TestParents(t *testing.T) {
+	gir := filepath.Join(t.TempDir(), "Atk-1.0.gir")
+	data := `<repository xmlns="http://www.gtk.org/introspection/core/1.0" xmlns:glib="http://www.gtk.org/introspection/glib/1.0">
+  <namespace name="Atk" version="1.0">
+    <class name="Object" parent="GObject.Object"/>
+    <class name="Root" parent="Object" glib:fundamental="1"/>
+  </namespace>
+</repository>`
+	if err := os.WriteFile(gir, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ns, err := ReadGIR(gir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Object": "GObject.Object", "Root": ""}
+	for _, obj := range ns.Classes {
+		if obj.Parent != want[obj.Name] {
+			t.Errorf("parent of %s is %q, want %q", obj.Name, obj.Parent, want[obj.Name])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JSONLoader reads namespaces from the files written by "go-gi dump",
+// which are named after the namespace and its version, i.e.
+// "Gtk-3.0.json".
+type JSONLoader struct {
+	Path []string
+}
+
+func (loader JSONLoader) Load(namespace, version string) (*Namespace, error) {
+	file := findFile(loader.Path, namespace, version, ".json")
+	if file == "" {
+		if version == "" {
+			return nil, fmt.Errorf("no .json file found for %s", namespace)
+		}
+		return nil, fmt.Errorf("no .json file found for %s-%s", namespace, version)
+	}
+	return ReadJSON(file)
+}
+
+// ReadJSON reads a single namespace written by WriteJSON.
+func ReadJSON(filename string) (*Namespace, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ns Namespace
+	if err := json.NewDecoder(f).Decode(&ns); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return &ns, nil
+}
+
+// WriteJSON writes the model of a namespace as indented JSON.
+func WriteJSON(w io.Writer, ns *Namespace) error {
+	data, err := json.MarshalIndent(ns, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestJSONRoundTrip checks that dumping namespaces read from .gir files
+// and loading them back gives the same model.
+func TestJSONRoundTrip(t *testing.T) {
+	gir, err := LoadAll(GIRLoader{Path: []string{filepath.Join("..", "testdata", "gotest")}}, "GoTest", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, ns := range gir {
+		f, err := os.Create(filepath.Join(dir, ns.Name+"-"+ns.Version+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteJSON(f, ns); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	json, err := LoadAll(JSONLoader{Path: []string{dir}}, "GoTest", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(json) != len(gir) {
+		t.Errorf("loaded %d namespaces back, want %d", len(json), len(gir))
+	}
+	for name, ns := range gir {
+		if !reflect.DeepEqual(json[name], ns) {
+			t.Errorf("%s changed on the way through JSON", name)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+// Package model describes GObject introspection data in plain Go, so the
+// generator doesn't care whether it was read from a typelib, a .gir file or
+// a JSON dump, and can be handed models built by hand.
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Namespace struct {
+	Name            string
+	Version         string
+	CPrefix         string
+	Includes        []string // in the form "Name-Version"
+	SharedLibraries []string // i.e. "libgtk-3.so.0"
+	Packages        []string // pkg-config packages; only known when read from a .gir file
+	CIncludes       []string // C headers; only known when read from a .gir file
+	Enums           []*Enumeration
+	Classes         []*Class
+	Interfaces      []*Interface
+	Constants       []*Constant
+	Functions       []*Callable         // the ones that don't belong to a type
+	Aliases         map[string]*TypeRef // what each alias stands for, by name; only known when read from a .gir file, since typelibs resolve them
+}
+
+type Enumeration struct {
+	Name              string
+	CType             string
+	Flags             bool // a bitfield, whose values are or'd together
+	Doc               string
+	Version           string // the version it was added in, i.e. "3.22"; empty if it isn't known
+	Deprecated        bool
+	DeprecatedVersion string // likewise, the version it was deprecated in
+	DeprecatedDoc     string // what to use instead
+	Values            []*Member
+}
+
+type Member struct {
+	Name        string
+	CIdentifier string
+	Doc         string
+	Value       int64
+}
+
+type Class struct {
+	Name              string
+	Namespace         string
+	CType             string
+	Doc               string
+	Parent            string // qualified, i.e. "GObject.Object"; empty if there isn't one
+	Abstract          bool
+	Fundamental       bool
+	Version           string
+	Deprecated        bool
+	DeprecatedVersion string
+	DeprecatedDoc     string
+	Interfaces        []string // qualified
+	Properties        []*Property
+	Methods           []*Callable
+	Signals           []*Callable
+}
+
+type Interface struct {
+	Name              string
+	Namespace         string
+	CType             string
+	Doc               string
+	Version           string
+	Deprecated        bool
+	DeprecatedVersion string
+	DeprecatedDoc     string
+	Prerequisites     []string // qualified
+	Properties        []*Property
+	Methods           []*Callable
+	Signals           []*Callable
+}
+
+type Property struct {
+	Name              string
+	Doc               string
+	Version           string
+	Deprecated        bool
+	DeprecatedVersion string
+	DeprecatedDoc     string
+	Type              *TypeRef
+	Transfer          Transfer
+	Readable          bool
+	Writable          bool
+	Construct         bool
+	ConstructOnly     bool
+}
+
+type Constant struct {
+	Name              string
+	CIdentifier       string
+	Doc               string
+	Version           string
+	Deprecated        bool
+	DeprecatedVersion string
+	DeprecatedDoc     string
+	Type              *TypeRef
+	Value             string // as text, i.e. "3" or "gtk-about"
+}
+
+// Callable describes functions, methods and signals; signals don't have a
+// symbol or flags.
+type Callable struct {
+	Name              string
+	Symbol            string
+	Doc               string
+	Version           string
+	Deprecated        bool
+	DeprecatedVersion string
+	DeprecatedDoc     string
+	Flags             FunctionFlags
+	Return            *TypeRef
+	ReturnTransfer    Transfer
+	ReturnDoc         string
+	MayReturnNull     bool
+	InstanceCType     string // what a method takes its instance as, i.e. "GtkWidget*"; only known when read from a .gir file
+	Params            []*Param
+	Unintrospectable  string // why it can't be bound, i.e. "takes varargs", if it can't; only known when read from a .gir file, since typelibs leave such callables out
+}
+
+type Param struct {
+	Name            string
+	Doc             string
+	Direction       Direction
+	Transfer        Transfer
+	Nullable        bool
+	Optional        bool
+	CallerAllocates bool
+	Skip            bool // left out of bindings, which pass it a zero value
+	Type            *TypeRef
+}
+
+type TypeRef struct {
+	Tag       TypeTag
+	Pointer   bool
+	CType     string // only known when read from a .gir file
+	Interface string // qualified name of the type, for InterfaceTag
+}
+
+// SortedEnums returns the enums of the namespace sorted by name.
+func (ns *Namespace) SortedEnums() []*Enumeration {
+	enums := append([]*Enumeration(nil), ns.Enums...)
+	sort.SliceStable(enums, func(i, j int) bool { return enums[i].Name < enums[j].Name })
+	return enums
+}
+
+// SortedClasses returns the classes of the namespace sorted by name.
+func (ns *Namespace) SortedClasses() []*Class {
+	classes := append([]*Class(nil), ns.Classes...)
+	sort.SliceStable(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	return classes
+}
+
+// SortedFunctions returns the functions of the namespace sorted by name.
+func (ns *Namespace) SortedFunctions() []*Callable {
+	return sortCallables(ns.Functions)
+}
+
+// SortedMethods returns the methods of the class sorted by name, then by
+// symbol.
+func (obj *Class) SortedMethods() []*Callable {
+	return sortCallables(obj.Methods)
+}
+
+func sortCallables(callables []*Callable) []*Callable {
+	sorted := append([]*Callable(nil), callables...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Symbol < sorted[j].Symbol
+	})
+	return sorted
+}
+
+// A Loader reads the introspection data of a single namespace. An empty
+// version means the latest one available.
+type Loader interface {
+	Load(namespace, version string) (*Namespace, error)
+}
+
+// CompareVersions compares dotted version numbers numerically, so that
+// 3.10 comes after 3.8, returning -1, 0 or 1 like strings.Compare.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Namespaces holds every loaded namespace by name.
+type Namespaces map[string]*Namespace
+
+// LoadAll loads namespace along with everything it includes.
+func LoadAll(loader Loader, namespace, version string) (Namespaces, error) {
+	all := make(Namespaces)
+	var load func(namespace, version string) error
+	load = func(namespace, version string) error {
+		if _, ok := all[namespace]; ok {
+			return nil
+		}
+		ns, err := loader.Load(namespace, version)
+		if err != nil {
+			return err
+		}
+		all[namespace] = ns
+		for _, include := range ns.Includes {
+			if err := load(SplitNamespace(include)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := load(namespace, version); err != nil {
+		return nil, err
+	}
+	all.ResolveAliases()
+	return all, nil
+}
+
+// ResolveAliases replaces every reference to an alias, like GLib.Quark,
+// with what it stands for, as the typelib compiler does. LoadAll does this
+// already, but namespaces read one at a time need it once they're all
+// read, since aliases can come from any of them.
+func (all Namespaces) ResolveAliases() {
+	for _, ns := range all {
+		ns.eachType(func(typ *TypeRef) {
+			target := all.alias(typ)
+			if target == nil {
+				return
+			}
+			typ.Tag, typ.Interface = target.Tag, target.Interface
+			if typ.CType == "" {
+				typ.Pointer = target.Pointer
+			}
+		})
+	}
+}
+
+// alias returns what typ stands for if it refers to an alias, following
+// aliases of aliases, or nil if it doesn't.
+func (all Namespaces) alias(typ *TypeRef) *TypeRef {
+	var target *TypeRef
+	for i := 0; i < 8 && typ.Tag == InterfaceTag; i++ {
+		ns, name := all.lookup(typ.Interface)
+		if ns == nil || ns.Aliases[name] == nil {
+			break
+		}
+		target = ns.Aliases[name]
+		typ = target
+	}
+	return target
+}
+
+// eachType calls f with every type referred to in the namespace.
+func (ns *Namespace) eachType(f func(*TypeRef)) {
+	callables := func(fns []*Callable) {
+		for _, fn := range fns {
+			if fn.Return != nil {
+				f(fn.Return)
+			}
+			for _, param := range fn.Params {
+				if param.Type != nil {
+					f(param.Type)
+				}
+			}
+		}
+	}
+	properties := func(props []*Property) {
+		for _, prop := range props {
+			if prop.Type != nil {
+				f(prop.Type)
+			}
+		}
+	}
+	for _, obj := range ns.Classes {
+		properties(obj.Properties)
+		callables(obj.Methods)
+		callables(obj.Signals)
+	}
+	for _, iface := range ns.Interfaces {
+		properties(iface.Properties)
+		callables(iface.Methods)
+		callables(iface.Signals)
+	}
+	callables(ns.Functions)
+	for _, c := range ns.Constants {
+		if c.Type != nil {
+			f(c.Type)
+		}
+	}
+}
+
+// Class looks up a class by its qualified name, returning nil if it
+// isn't in any of the loaded namespaces.
+func (all Namespaces) Class(name string) *Class {
+	ns, name := all.lookup(name)
+	if ns == nil {
+		return nil
+	}
+	for _, obj := range ns.Classes {
+		if obj.Name == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+// Enum looks up an enum by its qualified name, returning nil if it isn't
+// in any of the loaded namespaces.
+func (all Namespaces) Enum(name string) *Enumeration {
+	ns, name := all.lookup(name)
+	if ns == nil {
+		return nil
+	}
+	for _, enum := range ns.Enums {
+		if enum.Name == name {
+			return enum
+		}
+	}
+	return nil
+}
+
+// Interface looks up an interface by its qualified name, returning nil if
+// it isn't in any of the loaded namespaces.
+func (all Namespaces) Interface(name string) *Interface {
+	ns, name := all.lookup(name)
+	if ns == nil {
+		return nil
+	}
+	for _, iface := range ns.Interfaces {
+		if iface.Name == name {
+			return iface
+		}
+	}
+	return nil
+}
+
+// lookup splits a qualified name, returning its namespace if that's loaded
+// and the unqualified name.
+func (all Namespaces) lookup(name string) (*Namespace, string) {
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return nil, name
+	}
+	return all[name[:i]], name[i+1:]
+}
+
+// DependencyOrder returns namespace and everything it includes, sorted so
+// that every namespace comes after all of its dependencies.
+func (all Namespaces) DependencyOrder(namespace string) []string {
+	var order []string
+	visited := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		ns, ok := all[name]
+		if !ok {
+			return
+		}
+		var deps []string
+		for _, include := range ns.Includes {
+			dep, _ := SplitNamespace(include)
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			visit(dep)
+		}
+		order = append(order, name)
+	}
+	visit(namespace)
+
+	return order
+}
+
+// QualifiedName joins a namespace and a name the way .gir files do.
+func QualifiedName(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// SplitNamespace splits a dependency string such as "GObject-2.0" into its
+// namespace and version.
+func SplitNamespace(dep string) (string, string) {
+	i := strings.LastIndex(dep, "-")
+	if i < 0 {
+		return dep, ""
+	}
+	return dep[:i], dep[i+1:]
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+)
+
+// TypeTag identifies the kind of a type. The values are the same as those
+// of GITypeTag, so tags read from libgirepository can be converted
+// directly.
+type TypeTag int
+
+const (
+	VoidTag TypeTag = iota
+	BooleanTag
+	Int8Tag
+	Uint8Tag
+	Int16Tag
+	Uint16Tag
+	Int32Tag
+	Uint32Tag
+	Int64Tag
+	Uint64Tag
+	FloatTag
+	DoubleTag
+	GTypeTag
+	Utf8Tag
+	FilenameTag
+	// non-basic types
+	ArrayTag
+	InterfaceTag
+	GListTag
+	GSListTag
+	GHashTag
+	ErrorTag
+	// another basic type
+	UnicharTag
+)
+
+// the names g_type_tag_to_string() gives them
+var typeTagNames = []string{
+	"void", "gboolean", "gint8", "guint8", "gint16", "guint16", "gint32", "guint32",
+	"gint64", "guint64", "gfloat", "gdouble", "GType", "utf8", "filename",
+	"array", "interface", "GList", "GSList", "GHashTable", "GError", "gunichar",
+}
+
+func (tag TypeTag) String() string {
+	if tag < 0 || int(tag) >= len(typeTagNames) {
+		return fmt.Sprintf("TypeTag(%d)", int(tag))
+	}
+	return typeTagNames[tag]
+}
+
+func (tag TypeTag) MarshalText() ([]byte, error) {
+	return []byte(tag.String()), nil
+}
+
+func (tag *TypeTag) UnmarshalText(text []byte) error {
+	for i, name := range typeTagNames {
+		if name == string(text) {
+			*tag = TypeTag(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown type tag %q", text)
+}
+
+// Direction is the direction of a parameter, with the same values as
+// GIDirection.
+type Direction int
+
+const (
+	In Direction = iota
+	Out
+	InOut
+)
+
+var directionNames = []string{"in", "out", "inout"}
+
+// String returns the name .gir files use for the direction.
+func (dir Direction) String() string {
+	if dir < 0 || int(dir) >= len(directionNames) {
+		return fmt.Sprintf("Direction(%d)", int(dir))
+	}
+	return directionNames[dir]
+}
+
+func (dir Direction) MarshalText() ([]byte, error) {
+	return []byte(dir.String()), nil
+}
+
+func (dir *Direction) UnmarshalText(text []byte) error {
+	for i, name := range directionNames {
+		if name == string(text) {
+			*dir = Direction(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown direction %q", text)
+}
+
+// Transfer says who owns a value once it has been passed, with the same
+// values as GITransfer.
+type Transfer int
+
+const (
+	Nothing Transfer = iota
+	Container
+	Everything
+)
+
+var transferNames = []string{"none", "container", "full"}
+
+// String returns the name .gir files use for the transfer.
+func (transfer Transfer) String() string {
+	if transfer < 0 || int(transfer) >= len(transferNames) {
+		return fmt.Sprintf("Transfer(%d)", int(transfer))
+	}
+	return transferNames[transfer]
+}
+
+func (transfer Transfer) MarshalText() ([]byte, error) {
+	return []byte(transfer.String()), nil
+}
+
+func (transfer *Transfer) UnmarshalText(text []byte) error {
+	for i, name := range transferNames {
+		if name == string(text) {
+			*transfer = Transfer(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown transfer %q", text)
+}
+
+// FunctionFlags describes what kind of function a callable is.
+type FunctionFlags struct {
+	IsMethod      bool
+	IsConstructor bool
+	IsGetter      bool
+	IsSetter      bool
+	WrapsVFunc    bool
+	Throws        bool
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// defaultInitialisms are the words spelled a particular way in Go names
+// instead of just being capitalized, so that get_uri becomes GetURI rather
+// than GetUri. Entries don't have to be initialisms: any word spelled with
+// capitals in it, like DBus or XAlign, is matched regardless of case.
+var defaultInitialisms = []string{
+	"API", "ASCII", "CPU", "CSS", "DBus", "DNS", "EOF", "FD", "GUID",
+	"HTML", "HTTP", "HTTPS", "ID", "IO", "IP", "JSON", "RGB", "RGBA", "SQL",
+	"SSL", "TCP", "TLS", "UDP", "UI", "UID", "URI", "URL", "UTF8", "UTF16",
+	"UUID", "XAlign", "XML", "YAlign",
+}
+
+// reservedParams are names that parameters can't have, because the
+// generated code uses them itself.
+var reservedParams = []string{"self", "retval", "C", "unsafe"}
+
+// Names decides what everything in a set of namespaces is called in Go.
+// Names are worked out up front, rather than as each thing is generated, so
+// that two things whose C names come out the same in Go can be told apart:
+// whichever comes first by name keeps it, and the others get a number on
+// the end, i.e. GetDBus2. Types keep their names, and take priority over
+// the functions and enum values that share their package. Callables that
+// can't be bound at all, like ones taking varargs, aren't named.
+type Names struct {
+	words map[string]string // initialisms by lower case
+	names map[string]string // by qualified name, i.e. "Gtk.Widget.show"
+	// the packages generated code can refer to, which parameters can't
+	// shadow: the standard ones and those of the namespaces
+	packages map[string]bool
+}
+
+// NewNames names everything in namespaces, spelling the words in extra
+// the way they're given on top of the usual initialisms. Functions and
+// methods that overrides rename are given their new names.
+func NewNames(namespaces model.Namespaces, extra []string, overrides Overrides) *Names {
+	names := &Names{words: make(map[string]string), names: make(map[string]string), packages: make(map[string]bool)}
+	for _, pkg := range stdPackages {
+		names.packages[pkg] = true
+	}
+	for name := range namespaces {
+		names.packages[strings.ToLower(name)] = true
+	}
+	for _, word := range defaultInitialisms {
+		names.words[strings.ToLower(word)] = word
+	}
+	for _, word := range extra {
+		names.words[strings.ToLower(word)] = word
+	}
+
+	for _, ns := range namespaces {
+		taken := make(map[string]bool)
+		for _, enum := range ns.Enums {
+			taken[enum.Name] = true
+		}
+		for _, obj := range ns.Classes {
+			taken[obj.Name] = true
+			taken[obj.Name+"Like"] = true
+		}
+		for _, iface := range ns.Interfaces {
+			taken[iface.Name] = true
+		}
+
+		for _, enum := range ns.SortedEnums() {
+			for _, value := range enum.Values {
+				qualified := model.QualifiedName(ns.Name, enum.Name) + "." + value.Name
+				names.names[qualified] = unique(enum.Name+names.CamelCase(value.Name), taken)
+			}
+		}
+		for _, fn := range ns.SortedFunctions() {
+			if fn.Unintrospectable != "" {
+				continue
+			}
+			names.names[model.QualifiedName(ns.Name, fn.Name)] = unique(names.callable(fn, overrides), taken)
+		}
+
+		for _, obj := range ns.SortedClasses() {
+			qualified := model.QualifiedName(ns.Name, obj.Name)
+			methods := make(map[string]bool)
+			for parent := obj; parent != nil; parent = namespaces.Class(parent.Parent) {
+				methods["As"+parent.Namespace+parent.Name] = true
+			}
+			for _, method := range obj.SortedMethods() {
+				if method.Unintrospectable != "" {
+					continue
+				}
+				names.names[qualified+"."+method.Name] = unique(names.callable(method, overrides), methods)
+			}
+		}
+	}
+	return names
+}
+
+// unique returns name, or name with the lowest number on the end that
+// makes it unique, and marks it as taken.
+func unique(name string, taken map[string]bool) string {
+	if taken[name] {
+		i := 2
+		for taken[name+strconv.Itoa(i)] {
+			i++
+		}
+		name += strconv.Itoa(i)
+	}
+	taken[name] = true
+	return name
+}
+
+// Value returns the Go name of a member of enum.
+func (names *Names) Value(namespace string, enum *model.Enumeration, value *model.Member) string {
+	return names.lookup(model.QualifiedName(namespace, enum.Name)+"."+value.Name, enum.Name+names.CamelCase(value.Name))
+}
+
+// Function returns the Go name of a function that doesn't belong to a type.
+func (names *Names) Function(namespace string, fn *model.Callable) string {
+	return names.lookup(model.QualifiedName(namespace, fn.Name), names.CamelCase(fn.Name))
+}
+
+// Method returns the Go name of a method of obj.
+func (names *Names) Method(obj *model.Class, method *model.Callable) string {
+	return names.lookup(model.QualifiedName(obj.Namespace, obj.Name)+"."+method.Name, names.CamelCase(method.Name))
+}
+
+func (names *Names) callable(fn *model.Callable, overrides Overrides) string {
+	if name := overrides.Name(fn.Symbol); name != "" {
+		return name
+	}
+	return names.CamelCase(fn.Name)
+}
+
+func (names *Names) lookup(qualified, fallback string) string {
+	if names != nil {
+		if name, ok := names.names[qualified]; ok {
+			return name
+		}
+	}
+	return fallback
+}
+
+// CamelCase turns a C name like get_uri, or a property or signal name like
+// notify-uri, into an exported Go name.
+func (names *Names) CamelCase(name string) string {
+	var b strings.Builder
+	for _, word := range splitName(name) {
+		b.WriteString(names.word(word))
+	}
+	return b.String()
+}
+
+// Param returns the Go name of a parameter, which is unexported and can't
+// be a keyword or shadow a predeclared identifier or a package: n_items
+// becomes nItems, type becomes type_, len becomes len_ and errors becomes
+// errors_.
+func (names *Names) Param(name string) string {
+	words := splitName(name)
+	if len(words) == 0 {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		b.WriteString(names.word(word))
+	}
+	param := b.String()
+	if token.IsKeyword(param) || types.Universe.Lookup(param) != nil || names.isPackage(param) {
+		param += "_"
+	}
+	return param
+}
+
+func (names *Names) isPackage(name string) bool {
+	if names == nil {
+		for _, pkg := range stdPackages {
+			if pkg == name {
+				return true
+			}
+		}
+		return false
+	}
+	return names.packages[name]
+}
+
+// Params returns the Go names of the parameters of fn, in order. Names
+// that would clash with each other, or with anything the generated code
+// uses, get a number on the end.
+func (names *Names) Params(fn *model.Callable) []string {
+	taken := make(map[string]bool)
+	for _, name := range reservedParams {
+		taken[name] = true
+	}
+	if fn.Flags.Throws {
+		taken["err"] = true
+	}
+	params := make([]string, len(fn.Params))
+	for i, param := range fn.Params {
+		params[i] = unique(names.Param(param.Name), taken)
+	}
+	return params
+}
+
+// word capitalizes a single word of a name, or spells it the way the
+// initialisms say to. Plurals of initialisms are understood too, so uris
+// becomes URIs.
+func (names *Names) word(word string) string {
+	lower := strings.ToLower(word)
+	if names != nil {
+		if spelled, ok := names.words[lower]; ok {
+			return spelled
+		}
+		if spelled, ok := names.words[strings.TrimSuffix(lower, "s")]; ok && len(lower) > 2 {
+			return spelled + "s"
+		}
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func splitName(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+}
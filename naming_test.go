@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"Name": true, "Name2": true}
+	var got []string
+	for _, name := range []string{"Name", "Name", "Size", "Size", "Name3"} {
+		got = append(got, unique(name, taken))
+	}
+	want := []string{"Name3", "Name4", "Size", "Size2", "Name32"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unique gave %q, want %q", got, want)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	names := NewNames(nil, []string{"GtkWidget"}, nil)
+	tests := []struct {
+		name, want string
+	}{
+		{"get_uri", "GetURI"},
+		{"get_uris", "GetURIs"},
+		{"notify-uri", "NotifyURI"},
+		{"get_dbus_connection", "GetDBusConnection"},
+		{"get_xalign", "GetXAlign"},
+		{"set_gtkwidget", "SetGtkWidget"},
+		{"get_utf8_name", "GetUTF8Name"},
+		{"get_ids", "GetIDs"},
+		// only words that are an initialism and an s are plurals of one
+		{"get_is", "GetIs"},
+		{"get_status", "GetStatus"},
+		{"ref", "Ref"},
+	}
+	for _, test := range tests {
+		if got := names.CamelCase(test.name); got != test.want {
+			t.Errorf("CamelCase(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParam(t *testing.T) {
+	names := NewNames(model.Namespaces{"GObject": {Name: "GObject"}}, nil, nil)
+	tests := []struct {
+		name, want string
+	}{
+		{"n_items", "nItems"},
+		{"uri", "uri"},
+		{"base_uri", "baseURI"},
+		{"type", "type_"},
+		{"func", "func_"},
+		{"len", "len_"},
+		{"string", "string_"},
+		{"error", "error_"},
+		{"new", "new_"},
+		{"nil", "nil_"},
+		{"errors", "errors_"},
+		{"gobject", "gobject_"},
+	}
+	for _, test := range tests {
+		if got := names.Param(test.name); got != test.want {
+			t.Errorf("Param(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParamRefs(t *testing.T) {
+	fn := &model.Callable{Params: []*model.Param{{Name: "n"}, {Name: "self"}, {Name: "n_items", Skip: true}}}
+	names := NewNames(nil, nil, nil)
+	got := paramRefs(fn, names.Params(fn), "Copies @n of @self, but not @n_items, to @nowhere.")
+	if want := "Copies n of self2, but not n_items, to @nowhere."; got != want {
+		t.Errorf("paramRefs gave %q, want %q", got, want)
+	}
+}
+
+func TestParams(t *testing.T) {
+	fn := &model.Callable{
+		Flags: model.FunctionFlags{Throws: true},
+		Params: []*model.Param{
+			{Name: "self"}, {Name: "retval"}, {Name: "err"}, {Name: "n_items"}, {Name: "n-items"},
+		},
+	}
+	want := []string{"self2", "retval2", "err2", "nItems", "nItems2"}
+	if got := NewNames(nil, nil, nil).Params(fn); !reflect.DeepEqual(got, want) {
+		t.Errorf("Params gave %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// Override changes how a single function or method is generated, to make
+// up for bad annotations upstream without patching the generator. Each
+// namespace can have a file of them, i.e. overrides/gtk.toml, with a table
+// for each C symbol:
+//
+//	[gtk_widget_get_name]
+//	name = "Name"        # the Go name to use instead of GetName
+//
+//	[gtk_widget_get_name.return]
+//	nullable = true
+//	transfer = "none"
+//
+//	[gtk_widget_set_name]
+//	throws = true        # its GError** is listed as an ordinary parameter
+//	body = '''
+//		return nil
+//	'''
+//
+//	[gtk_widget_set_name.params.name]
+//	skip = true          # leave it out and pass a zero value
+//	nullable = true
+//	transfer = "full"
+//
+//	[gtk_widget_get_preferred_width.params.minimum_width]
+//	direction = "out"    # a gint* it fills in, not one it reads
+//
+// The keys are named after the annotations they stand in for. Throwing
+// drops a GError** that's the last parameter, since the generated code
+// passes its own, and changing the direction of a parameter between in
+// and out or inout changes its type to what it points to or back. A body
+// replaces everything in the function that calls C, and is written as it
+// goes in the function, using the Go names of the parameters.
+type Override struct {
+	Name   string
+	Throws *bool
+	Body   string
+	Return *ParamOverride
+	Params map[string]*ParamOverride // by C name
+}
+
+// ParamOverride forces the annotations of a parameter or return value.
+// Anything nil is left as it is.
+type ParamOverride struct {
+	Skip      *bool
+	Nullable  *bool
+	Transfer  *model.Transfer
+	Direction *model.Direction
+}
+
+// Overrides holds the overrides of every generated namespace by C symbol.
+type Overrides map[string]*Override
+
+func (g *Generator) namespaceOverrides(namespace string) (Overrides, error) {
+	if g.Overrides == nil {
+		return Overrides{}, nil
+	}
+	filename := strings.ToLower(namespace) + ".toml"
+	data, err := fs.ReadFile(g.Overrides, filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Overrides{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return parseOverrides(filename, data)
+}
+
+func parseOverrides(filename string, data []byte) (Overrides, error) {
+	entries, err := parseTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", filename, err)
+	}
+	overrides := make(Overrides)
+	for _, entry := range entries {
+		if err := overrides.set(entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, entry.line, err)
+		}
+	}
+	return overrides, nil
+}
+
+func (overrides Overrides) set(entry tomlEntry) error {
+	if len(entry.table) == 0 {
+		return fmt.Errorf("%s isn't in the table of a symbol", entry.key)
+	}
+	over, ok := overrides[entry.table[0]]
+	if !ok {
+		over = &Override{Params: make(map[string]*ParamOverride)}
+		overrides[entry.table[0]] = over
+	}
+
+	switch {
+	case len(entry.table) == 1:
+		switch entry.key {
+		case "name":
+			return entry.as(&over.Name)
+		case "throws":
+			over.Throws = new(bool)
+			return entry.as(over.Throws)
+		case "body":
+			if err := entry.as(&over.Body); err != nil {
+				return err
+			}
+			over.Body = strings.Trim(over.Body, "\n") + "\n"
+			return nil
+		}
+	case len(entry.table) == 2 && entry.table[1] == "return":
+		if over.Return == nil {
+			over.Return = &ParamOverride{}
+		}
+		if entry.key != "direction" && entry.key != "skip" {
+			return over.Return.set(entry)
+		}
+	case len(entry.table) == 3 && entry.table[1] == "params":
+		param, ok := over.Params[entry.table[2]]
+		if !ok {
+			param = &ParamOverride{}
+			over.Params[entry.table[2]] = param
+		}
+		return param.set(entry)
+	default:
+		return fmt.Errorf("unknown table [%s]", strings.Join(entry.table, "."))
+	}
+	return fmt.Errorf("unknown key %s in [%s]", entry.key, strings.Join(entry.table, "."))
+}
+
+func (param *ParamOverride) set(entry tomlEntry) error {
+	var text string
+	switch entry.key {
+	case "skip":
+		param.Skip = new(bool)
+		return entry.as(param.Skip)
+	case "nullable":
+		param.Nullable = new(bool)
+		return entry.as(param.Nullable)
+	case "transfer":
+		param.Transfer = new(model.Transfer)
+		if err := entry.as(&text); err != nil {
+			return err
+		}
+		return param.Transfer.UnmarshalText([]byte(text))
+	case "direction":
+		param.Direction = new(model.Direction)
+		if err := entry.as(&text); err != nil {
+			return err
+		}
+		return param.Direction.UnmarshalText([]byte(text))
+	}
+	return fmt.Errorf("unknown key %s in [%s]", entry.key, strings.Join(entry.table, "."))
+}
+
+// Apply forces the annotations of the functions and methods in ns that
+// have overrides. Overrides for symbols or parameters that ns doesn't have
+// are reported, since they're most likely left over from an older version
+// of the library.
+func (overrides Overrides) Apply(ns *model.Namespace) {
+	found := make(map[string]bool)
+	apply := func(fn *model.Callable) {
+		over, ok := overrides[fn.Symbol]
+		if !ok {
+			return
+		}
+		found[fn.Symbol] = true
+		if over.Throws != nil {
+			fn.Flags.Throws = *over.Throws
+			if n := len(fn.Params); fn.Flags.Throws && n > 0 && fn.Params[n-1].Type.Tag == model.ErrorTag {
+				fn.Params = fn.Params[:n-1]
+			}
+		}
+		if over.Return != nil {
+			if over.Return.Nullable != nil {
+				fn.MayReturnNull = *over.Return.Nullable
+			}
+			if over.Return.Transfer != nil {
+				fn.ReturnTransfer = *over.Return.Transfer
+			}
+		}
+		params := make(map[string]*model.Param)
+		for _, param := range fn.Params {
+			params[param.Name] = param
+		}
+		for name, po := range over.Params {
+			param, ok := params[name]
+			if !ok {
+				fmt.Fprintln(os.Stderr, "[!] Override for unknown parameter "+name+" of "+fn.Symbol)
+				continue
+			}
+			po.apply(param)
+		}
+	}
+	for _, fn := range ns.Functions {
+		apply(fn)
+	}
+	for _, obj := range ns.Classes {
+		for _, method := range obj.Methods {
+			apply(method)
+		}
+	}
+	for _, iface := range ns.Interfaces {
+		for _, method := range iface.Methods {
+			apply(method)
+		}
+	}
+
+	var unknown []string
+	for symbol := range overrides {
+		if !found[symbol] {
+			unknown = append(unknown, symbol)
+		}
+	}
+	sort.Strings(unknown)
+	for _, symbol := range unknown {
+		fmt.Fprintln(os.Stderr, "[!] Override for unknown symbol "+symbol+" in "+ns.Name)
+	}
+}
+
+func (po *ParamOverride) apply(param *model.Param) {
+	if po.Skip != nil {
+		param.Skip = *po.Skip
+	}
+	if po.Nullable != nil {
+		param.Nullable = *po.Nullable
+	}
+	if po.Transfer != nil {
+		param.Transfer = *po.Transfer
+	}
+	if po.Direction != nil {
+		wasIn, isIn := param.Direction == model.In, *po.Direction == model.In
+		if wasIn != isIn && !param.CallerAllocates && param.Type.Tag != model.VoidTag {
+			// out and inout parameters are described by what they point to
+			if isIn {
+				param.Type.Pointer = true
+			} else {
+				param.Type.Pointer = pointsToPointer(param.Type)
+			}
+		}
+		param.Direction = *po.Direction
+	}
+}
+
+// pointsToPointer returns whether what a pointer of type typ points to is
+// a pointer too. Without a C type, that's guessed from the tag, the way
+// the .gir frontend guesses whether types are pointers.
+func pointsToPointer(typ *model.TypeRef) bool {
+	if typ.CType != "" {
+		return strings.Count(typ.CType, "*") > 1
+	}
+	switch typ.Tag {
+	case model.Utf8Tag, model.FilenameTag, model.GListTag, model.GSListTag, model.GHashTag, model.ErrorTag, model.InterfaceTag:
+		return true
+	}
+	return false
+}
+
+// Name returns the Go name symbol is renamed to, or "" if it isn't.
+func (overrides Overrides) Name(symbol string) string {
+	if over, ok := overrides[symbol]; ok {
+		return over.Name
+	}
+	return ""
+}
+
+// Body returns the hand-written body of symbol, or "" if it doesn't have one.
+func (overrides Overrides) Body(symbol string) string {
+	if over, ok := overrides[symbol]; ok {
+		return over.Body
+	}
+	return ""
+}
+
+// tomlEntry is a single key and value from a TOML file, along with the
+// table it's in.
+type tomlEntry struct {
+	table []string
+	key   string
+	value interface{} // a string or a bool
+	line  int
+}
+
+func (entry tomlEntry) as(dst interface{}) error {
+	switch dst := dst.(type) {
+	case *string:
+		if s, ok := entry.value.(string); ok {
+			*dst = s
+			return nil
+		}
+		return fmt.Errorf("%s should be a string", entry.key)
+	case *bool:
+		if b, ok := entry.value.(bool); ok {
+			*dst = b
+			return nil
+		}
+		return fmt.Errorf("%s should be true or false", entry.key)
+	}
+	panic("unsupported type")
+}
+
+// parseTOML reads the subset of TOML that overrides need: comments,
+// [tables] with dotted names, and keys whose values are booleans or
+// strings, including multi-line ones. Keys can't be dotted, and nothing
+// else, such as numbers, arrays or inline tables, is understood.
+func parseTOML(text string) ([]tomlEntry, error) {
+	var entries []tomlEntry
+	var table []string
+	line := 1
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%d: "+format, append([]interface{}{line}, args...)...)
+	}
+	seen := make(map[string]bool)
+	for text != "" {
+		var rest string
+		switch {
+		case text[0] == '\n':
+			line++
+			text = text[1:]
+			continue
+		case text[0] == ' ' || text[0] == '\t' || text[0] == '\r':
+			text = text[1:]
+			continue
+		case text[0] == '#':
+			text = skipComment(text)
+			continue
+		case text[0] == '[':
+			end := strings.IndexByte(text, ']')
+			if end < 0 || strings.Contains(text[:end], "\n") {
+				return nil, errorf("unterminated table name")
+			}
+			table = nil
+			for _, part := range strings.Split(text[1:end], ".") {
+				name, err := tomlKey(strings.TrimSpace(part))
+				if err != nil {
+					return nil, errorf("%s", err)
+				}
+				table = append(table, name)
+			}
+			rest = text[end+1:]
+		default:
+			eq := strings.IndexByte(text, '=')
+			if eq < 0 || strings.Contains(text[:eq], "\n") {
+				return nil, errorf("expected a key = value")
+			}
+			key, err := tomlKey(strings.TrimSpace(text[:eq]))
+			if err != nil {
+				return nil, errorf("%s", err)
+			}
+			full := strings.Join(append(table, key), ".")
+			if seen[full] {
+				return nil, errorf("%s is set twice", full)
+			}
+			seen[full] = true
+			value, after, lines, err := tomlValue(strings.TrimLeft(text[eq+1:], " \t"))
+			if err != nil {
+				return nil, errorf("%s: %s", key, err)
+			}
+			entries = append(entries, tomlEntry{table: table, key: key, value: value, line: line})
+			line += lines
+			rest = after
+		}
+		// only a comment can follow on the same line
+		rest = strings.TrimLeft(rest, " \t\r")
+		if rest != "" && rest[0] == '#' {
+			rest = skipComment(rest)
+		}
+		if rest != "" && rest[0] != '\n' {
+			return nil, errorf("unexpected %q", strings.SplitN(rest, "\n", 2)[0])
+		}
+		text = rest
+	}
+	return entries, nil
+}
+
+func skipComment(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[i:]
+	}
+	return ""
+}
+
+// tomlKey reads a bare or quoted key.
+func tomlKey(key string) (string, error) {
+	if strings.HasPrefix(key, `"`) {
+		return strconv.Unquote(key)
+	}
+	if key == "" {
+		return "", errors.New("empty key")
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '-' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return "", fmt.Errorf("invalid key %q", key)
+		}
+	}
+	return key, nil
+}
+
+// tomlValue reads the value at the start of text, returning it along with
+// the rest of text and how many lines the value spanned.
+func tomlValue(text string) (interface{}, string, int, error) {
+	for _, delim := range []string{`"""`, `'''`} {
+		if !strings.HasPrefix(text, delim) {
+			continue
+		}
+		end := closingQuotes(text[3:], delim)
+		if end < 0 {
+			return nil, "", 0, fmt.Errorf("unterminated string")
+		}
+		raw := text[3 : 3+end]
+		lines := strings.Count(raw, "\n")
+		// a newline straight after the opening quotes isn't part of it
+		raw = strings.TrimPrefix(strings.TrimPrefix(raw, "\r"), "\n")
+		if delim == `"""` {
+			s, err := unescape(raw)
+			return s, text[3+end+3:], lines, err
+		}
+		return raw, text[3+end+3:], lines, nil
+	}
+
+	switch {
+	case strings.HasPrefix(text, `"`):
+		for i := 1; i < len(text) && text[i] != '\n'; i++ {
+			if text[i] == '\\' {
+				i++
+			} else if text[i] == '"' {
+				s, err := unescape(text[1:i])
+				return s, text[i+1:], 0, err
+			}
+		}
+		return nil, "", 0, fmt.Errorf("unterminated string")
+	case strings.HasPrefix(text, `'`):
+		end := strings.IndexAny(text[1:], "'\n")
+		if end < 0 || text[1+end] != '\'' {
+			return nil, "", 0, fmt.Errorf("unterminated string")
+		}
+		return text[1 : 1+end], text[1+end+1:], 0, nil
+	case strings.HasPrefix(text, "true"):
+		return true, text[4:], 0, nil
+	case strings.HasPrefix(text, "false"):
+		return false, text[5:], 0, nil
+	}
+	return nil, "", 0, fmt.Errorf("only strings and booleans are supported")
+}
+
+// closingQuotes returns the index of the delim that ends a multi-line
+// string, skipping escaped quotes in basic ones, or -1 if there isn't one.
+func closingQuotes(text, delim string) int {
+	for i := 0; i+len(delim) <= len(text); i++ {
+		if delim == `"""` && text[i] == '\\' {
+			i++
+		} else if strings.HasPrefix(text[i:], delim) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape handles the escapes of TOML's basic strings.
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", errors.New("trailing backslash")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		case 'u', 'U':
+			n := 4
+			if s[i] == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return "", errors.New("short unicode escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape: %w", err)
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
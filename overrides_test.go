@@ -0,0 +1,193 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       []tomlEntry
+		err        string // a prefix of the error, which starts with the line
+	}{
+		{
+			name: "tables",
+			text: "# comment\n[a]\nname = \"A\" # trailing\n\n[a.params.\"x y\"]\nskip = true\n",
+			want: []tomlEntry{
+				{table: []string{"a"}, key: "name", value: "A", line: 3},
+				{table: []string{"a", "params", "x y"}, key: "skip", value: true, line: 6},
+			},
+		},
+		{
+			name: "spaced table name",
+			text: "[ a . return ]\nnullable = false",
+			want: []tomlEntry{{table: []string{"a", "return"}, key: "nullable", value: false, line: 2}},
+		},
+		{
+			name: "multi-line strings",
+			text: "[a]\nbody = '''\n\treturn \\n\n'''\nname = \"\"\"\nX\\tY\"\"\"\nthrows = true\n",
+			want: []tomlEntry{
+				{table: []string{"a"}, key: "body", value: "\treturn \\n\n", line: 2},
+				{table: []string{"a"}, key: "name", value: "X\tY", line: 5},
+				{table: []string{"a"}, key: "throws", value: true, line: 7},
+			},
+		},
+		{
+			name: "literal string",
+			text: `key = 'C:\path "quoted"'`,
+			want: []tomlEntry{{key: "key", value: `C:\path "quoted"`, line: 1}},
+		},
+		{name: "unterminated table", text: "[a\n]", err: "1: unterminated table name"},
+		{name: "missing value", text: "[a]\n\nname\n", err: "3: expected a key = value"},
+		{name: "set twice", text: "[a]\nx = true\n[b]\nx = true\n[a]\nx = false", err: "6: a.x is set twice"},
+		{name: "number", text: "[a]\nx = 1", err: "2: x: only strings and booleans are supported"},
+		{name: "unterminated string", text: "[a]\nx = \"abc\ny = true", err: "2: x: unterminated string"},
+		{name: "unterminated multi-line string", text: "x = '''\nabc", err: "1: x: unterminated string"},
+		{name: "bad escape", text: "\n\nx = \"\\q\"", err: `3: x: invalid escape \q`},
+		{name: "trailing junk", text: "[a]\nx = true false", err: `2: unexpected "false"`},
+		{name: "invalid key", text: "a.b = true", err: `1: invalid key "a.b"`},
+		{name: "line after multi-line string", text: "x = '''\n\n'''\ny", err: "4: expected a key = value"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseTOML(test.text)
+			if test.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+					t.Fatalf("got error %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		in, want, err string
+	}{
+		{in: `plain`, want: "plain"},
+		{in: `a\tb\nc\rd`, want: "a\tb\nc\rd"},
+		{in: `say \"hi\" \\o/`, want: `say "hi" \o/`},
+		{in: `\u00e9t\u00E9`, want: "été"},
+		{in: `\U0001F600`, want: "\U0001F600"},
+		{in: `trailing\`, err: "trailing backslash"},
+		{in: `\u00e`, err: "short unicode escape"},
+		{in: `\u00zz`, err: "invalid unicode escape"},
+		{in: `\a`, err: `invalid escape \a`},
+	}
+	for _, test := range tests {
+		got, err := unescape(test.in)
+		switch {
+		case test.err != "" && (err == nil || !strings.HasPrefix(err.Error(), test.err)):
+			t.Errorf("unescape(%q) gave error %v, want %q", test.in, err, test.err)
+		case test.err == "" && err != nil:
+			t.Errorf("unescape(%q): %v", test.in, err)
+		case got != test.want:
+			t.Errorf("unescape(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestTOMLValue(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  interface{}
+		rest  string
+		lines int
+	}{
+		{in: `"a\"b" # c`, want: `a"b`, rest: " # c"},
+		{in: `'a\"b'`, want: `a\"b`},
+		{in: "'''\nx\ny\n'''\nz", want: "x\ny\n", rest: "\nz", lines: 3},
+		{in: "\"\"\"a\\\"\"\"\"", want: `a"`},
+		{in: "true\n", want: true, rest: "\n"},
+		{in: "false", want: false},
+	}
+	for _, test := range tests {
+		got, rest, lines, err := tomlValue(test.in)
+		if err != nil {
+			t.Errorf("tomlValue(%q): %v", test.in, err)
+			continue
+		}
+		if got != test.want || rest != test.rest || lines != test.lines {
+			t.Errorf("tomlValue(%q) = %q, %q, %d, want %q, %q, %d", test.in, got, rest, lines, test.want, test.rest, test.lines)
+		}
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	overrides, err := parseOverrides("gotest.toml", []byte(`
+[go_test_thing_get_name]
+name = "Name"
+
+[go_test_thing_get_name.return]
+nullable = true
+transfer = "full"
+
+[go_test_thing_set_uri.params.type]
+skip = true
+direction = "inout"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	yes, full, inout := true, model.Everything, model.InOut
+	want := Overrides{
+		"go_test_thing_get_name": {
+			Name:   "Name",
+			Return: &ParamOverride{Nullable: &yes, Transfer: &full},
+			Params: map[string]*ParamOverride{},
+		},
+		"go_test_thing_set_uri": {
+			Params: map[string]*ParamOverride{"type": {Skip: &yes, Direction: &inout}},
+		},
+	}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Errorf("got %+v, want %+v", overrides, want)
+	}
+
+	for text, wantErr := range map[string]string{
+		"name = \"Name\"":                     "gotest.toml:1: name isn't in the table of a symbol",
+		"[a]\nnullable = true":                "gotest.toml:2: unknown key nullable in [a]",
+		"[a.return]\nskip = true":             "gotest.toml:2: unknown key skip in [a.return]",
+		"[a.params]\nskip = true":             "gotest.toml:2: unknown table [a.params]",
+		"[a.params.b]\n\ntransfer = \"some\"": "gotest.toml:3: ",
+		"[a]\nthrows = \"yes\"":               "gotest.toml:2: throws should be true or false",
+		"[a]\nname = 'A'\n[b]\nname = 'B\n":   "gotest.toml:4: name: unterminated string",
+	} {
+		if _, err := parseOverrides("gotest.toml", []byte(text)); err == nil || !strings.HasPrefix(err.Error(), wantErr) {
+			t.Errorf("parsing %q gave error %v, want %q", text, err, wantErr)
+		}
+	}
+}
+
+func TestApplyDirection(t *testing.T) {
+	out, in := model.Out, model.In
+	tests := []struct {
+		param     model.Param
+		direction *model.Direction
+		want      bool // whether the type is a pointer afterwards
+	}{
+		{model.Param{Direction: model.In, Type: &model.TypeRef{Tag: model.Int32Tag, Pointer: true, CType: "gint*"}}, &out, false},
+		{model.Param{Direction: model.In, Type: &model.TypeRef{Tag: model.Utf8Tag, Pointer: true, CType: "gchar**"}}, &out, true},
+		{model.Param{Direction: model.In, Type: &model.TypeRef{Tag: model.Int32Tag, Pointer: true}}, &out, false},
+		{model.Param{Direction: model.Out, Type: &model.TypeRef{Tag: model.Int32Tag, CType: "gint*"}}, &in, true},
+		{model.Param{Direction: model.Out, CallerAllocates: true, Type: &model.TypeRef{Tag: model.InterfaceTag, Pointer: true, CType: "GdkRGBA*"}}, &in, true},
+	}
+	for _, test := range tests {
+		param := test.param
+		(&ParamOverride{Direction: test.direction}).apply(&param)
+		if param.Direction != *test.direction || param.Type.Pointer != test.want {
+			t.Errorf("overriding the direction of %+v to %s gave %s with pointer %t, want pointer %t", *test.param.Type, test.direction, param.Direction, param.Type.Pointer, test.want)
+		}
+	}
+}
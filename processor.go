@@ -3,11 +3,12 @@ package main
 import (
 	"container/list"
 	"bytes"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"text/template"
+
+	"github.com/dradtke/go-gi/model"
 )
 
 /* --- Enums --- */
@@ -15,32 +16,40 @@ import (
 type EnumDefinition struct {
 	EnumName string
 	CType    string
+	Doc      string
 	Values   []EnumValue
 }
 
 type EnumValue struct {
-	Name     string
+	Name     string // in full, i.e. "ColorRed"
 	EnumName string
+	Doc      string
 	Value    int64
 }
 
-func ProcessEnum(info *BaseInfo, code *bytes.Buffer, tmpl *template.Template, blacklist *map[string] bool) {
-	if info.IsDeprecated() {
+func ProcessEnum(enum *model.Enumeration, namespace string, code *Code, tmpl *template.Template, blacklist *map[string] bool, deprecated bool, names *Names, docs *DocWriter, cov *coverage) {
+	qualified := model.QualifiedName(namespace, enum.Name)
+	kind := "enum"
+	if enum.Flags {
+		kind = "flags"
+	}
+	if enum.Deprecated && !deprecated {
+		cov.skip(kind, qualified, SkipDeprecated, since(enum.DeprecatedVersion))
 		return
 	}
+	cov.bind(kind, qualified)
 
-	name := info.GetName()
-	prefix := GetCPrefix(info.GetNamespace())
-	def := &EnumDefinition{EnumName:name, CType:prefix+name}
+	name := enum.Name
+	def := &EnumDefinition{EnumName:name, CType:enum.CType}
+	deprecation := docs.Deprecated(namespace, enum.Deprecated, enum.DeprecatedVersion, enum.DeprecatedDoc)
+	def.Doc = docs.Type(namespace, name, enum.CType, enum.Doc, deprecation, nil, nil)
 
-	numValues := info.GetNEnumValues()
-	for i := 0; i < numValues; i++ {
-		value := info.GetEnumValue(i)
-		valDef := EnumValue{Name:CamelCase(value.GetName()), EnumName:name, Value:value.GetValue()}
+	for _, value := range enum.Values {
+		valDef := EnumValue{Name:names.Value(namespace, enum, value), EnumName:name, Doc:docs.Value(namespace, value.Doc), Value:value.Value}
 		def.Values = append(def.Values, valDef)
 	}
 
-	err := tmpl.ExecuteTemplate(code, "enum", def)
+	err := tmpl.ExecuteTemplate(code.For(namespace, enum.Version), "enum", def)
 	if err != nil {
 		fmt.Println(err.Error())
 	}
@@ -54,80 +63,91 @@ type ObjectDefinition struct {
 	CType         string
 	CastFunc      string
 	Namespace     string
+	Package       string
+	Version       string // of the library it was added in
+	Doc           string
 }
 
-func (info *BaseInfo) GetObjectDefinition() ObjectDefinition {
-	name, namespace := info.GetName(), info.GetNamespace()
-	prefix := GetCPrefix(namespace)
+func NewObjectDefinition(obj *model.Class) ObjectDefinition {
+	name, namespace := obj.Name, obj.Namespace
 	return ObjectDefinition{
 		ObjectName:    name,
 		InterfaceName: name + "Like",
-		CType:         prefix + name,
-		CastFunc:      "as" + namespace + name,
+		CType:         obj.CType,
+		// exported so that objects in other namespaces can implement it
+		CastFunc:      "As" + namespace + name,
 		Namespace:     namespace,
+		Package:       strings.ToLower(namespace),
+		Version:       obj.Version,
 	}
 }
 
-func ProcessObject(info *BaseInfo, code *bytes.Buffer, tmpl *template.Template, exists *map[string] bool, blacklist *map[string] bool) {
-	if info.IsDeprecated() {
+func ProcessObject(obj *model.Class, namespaces model.Namespaces, code *Code, tmpl *template.Template, exists *map[string] bool, blacklists map[string]map[string] bool, overrides Overrides, deprecated bool, names *Names, docs *DocWriter, cov *coverage) {
+	if obj.Deprecated && !deprecated {
+		cov.skipClass(obj, SkipDeprecated, since(obj.DeprecatedVersion))
 		return
 	}
+	qualified := model.QualifiedName(obj.Namespace, obj.Name)
+	cov.bind("class", qualified)
+	cov.skipMembers(qualified, obj.Properties, obj.Signals, SkipNotImplemented, "")
 
 	var err error
-	def := info.GetObjectDefinition()
+	def := NewObjectDefinition(obj)
+	deprecation := docs.Deprecated(obj.Namespace, obj.Deprecated, obj.DeprecatedVersion, obj.DeprecatedDoc)
+	def.Doc = docs.Type(obj.Namespace, def.ObjectName, obj.CType, obj.Doc, deprecation, obj.Properties, obj.Signals)
+
+	out := code.For(obj.Namespace, obj.Version)
 
 	// write object definition
-	err = tmpl.ExecuteTemplate(code, "object-definition", def)
+	err = tmpl.ExecuteTemplate(out, "object-definition", def)
 	if err != nil {
 		fmt.Println(err.Error())
 	}
 
 	// write interface definition
-	err = tmpl.ExecuteTemplate(code, "interface-definition", def)
+	err = tmpl.ExecuteTemplate(out, "interface-definition", def)
 	if err != nil {
 		fmt.Println(err.Error())
 	}
 
-	implementAll(def, info, code, tmpl)
-	writeMethods(&def, info, code, tmpl, exists, blacklist, "")
+	implementAll(def, obj, namespaces, out, tmpl)
+	blacklist := blacklists[obj.Namespace]
+	writeMethods(&def, obj, code, tmpl, exists, &blacklist, overrides, "", deprecated, names, docs, cov)
 
-	for hasParent(info) {
-		info = info.GetParent()
-		writeMethods(&def, info, code, tmpl, exists, blacklist, info.GetName())
-		if info.GetNamespace() != def.Namespace {
-			name := info.GetName()
-			if !(*exists)[name] {
-				tmpl.ExecuteTemplate(code, "interface-definition", info.GetObjectDefinition())
-			}
-			(*exists)[name] = true
-		}
+	// inherited methods count towards the coverage of their own class, and
+	// are blacklisted by their own namespace
+	for parent := namespaces.Class(obj.Parent); parent != nil; parent = namespaces.Class(parent.Parent) {
+		blacklist := blacklists[parent.Namespace]
+		writeMethods(&def, parent, code, tmpl, exists, &blacklist, overrides, parent.Name, deprecated, names, docs, nil)
 	}
 }
 
-func writeMethods(def *ObjectDefinition, info *BaseInfo, code *bytes.Buffer, tmpl *template.Template, exists *map[string] bool, blacklist *map[string] bool, className string) {
-	numMethods := info.GetNObjectMethods()
-	for i := 0; i < numMethods; i++ {
-		method := info.GetObjectMethod(i)
-		symbol := method.GetSymbol()
+func writeMethods(def *ObjectDefinition, obj *model.Class, code *Code, tmpl *template.Template, exists *map[string] bool, blacklist *map[string] bool, overrides Overrides, className string, deprecated bool, names *Names, docs *DocWriter, cov *coverage) {
+	for _, method := range obj.SortedMethods() {
+		symbol := method.Symbol
+		qualified := model.QualifiedName(obj.Namespace, obj.Name) + "." + method.Name
 
-		if (*blacklist)[symbol] || method.IsDeprecated() {
+		if reason, detail := skipReason(method, *blacklist, deprecated); reason != "" {
+			cov.skip("method", qualified, reason, detail)
 			continue
 		}
 
-		flags := method.GetFunctionFlags()
-		name := method.GetName()
+		flags := method.Flags
+		name := names.Method(obj, method)
 
 		methodName := def.ObjectName + "." + name
 		if (*exists)[methodName] {
+			cov.skip("method", qualified, SkipCollision, methodName + " is already defined")
 			continue
 		}
 		(*exists)[methodName] = true
 
-		goargs, gorets, cargs, crets, err := readParams(method, flags)
+		goargs, gorets, cargs, crets, err := readParams(method, names)
 		if err != nil {
-			// TODO: log this
+			cov.skip("method", qualified, SkipUnsupported, err.Error())
 			continue
 		}
+		cov.bind("method", qualified)
 
 		fn := FunctionDefinition{
 			Name:name,
@@ -136,69 +156,139 @@ func writeMethods(def *ObjectDefinition, info *BaseInfo, code *bytes.Buffer, tmp
 			ForGo:ArgsAndRets{Args:goargs, Rets:gorets},
 			ForC:ArgsAndRets{Args:cargs, Rets:crets},
 			Flags:flags,
-			Info:method,
+			Function:method,
+			Doc:docs.Function(def.Namespace, name, method),
+			Body:overrides.Body(symbol),
 		}
 		if className != "" {
 			fn.ClassName = className
 		}
 
-		var marshal bytes.Buffer
-		for _, param := range cargs {
-			switch param.Dir {
-				case In, InOut: tmpl.ExecuteTemplate(&marshal, "c-marshal", param)
-				case Out: tmpl.ExecuteTemplate(&marshal, "c-decl", param)
+		fn.ArgMarshalBody, fn.RetMarshalBody = marshalBodies(cargs, gorets, tmpl)
+
+		out := code.ForMember(def.Namespace, def.Version, obj.Namespace, method.Version)
+
+		if obj.Namespace != def.Namespace {
+			// methods of objects in other namespaces are generated in their
+			// own package, so just forward to them
+			foreign := NewObjectDefinition(obj)
+			fn.Foreign = &foreign
+			out.Imports[foreign.Package] = true
+			err := tmpl.ExecuteTemplate(out, "go-function-foreign", fn)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
 			}
+			continue
 		}
-		fn.ArgMarshalBody = marshal.String()
-		marshal.Reset()
-		for _, ret := range crets {
-			tmpl.ExecuteTemplate(&marshal, "go-marshal", ret)
-		}
-		fn.RetMarshalBody = marshal.String()
 
-		tmpl.ExecuteTemplate(code, "go-function-wrapper", fn)
+		tmpl.ExecuteTemplate(out, "go-function-wrapper", fn)
 		if className == "" {
-			err := tmpl.ExecuteTemplate(code, "go-function", fn)
+			// the wrapper is the one that's documented
+			fn.Doc = ""
+			err := tmpl.ExecuteTemplate(out, "go-function", fn)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, err)
 			}
-		} else if info.GetNamespace() != def.Namespace {
-			// need to implement methods for objects that aren't in this namespace
-			newDef := info.GetObjectDefinition()
-			fn.Owner = &newDef
-			fn.ClassName = fn.Owner.ObjectName
-			methodName = newDef.ObjectName + "." + name
-			if !(*exists)[methodName] {
-				tmpl.ExecuteTemplate(code, "go-function", fn)
-				if symbol == "g_object_is_floating" {
-					fmt.Println("found it!")
-					fmt.Printf("owned: %b\n", fn.HasOwner())
-					fmt.Printf("number of args: %d\n", fn.Info.GetNArgs())
-				}
-			}
-			(*exists)[methodName] = true
 		}
 	}
 }
 
-func implementAll(def ObjectDefinition, face *BaseInfo, code *bytes.Buffer, tmpl *template.Template) {
-	impl := face.GetObjectDefinition()
+// skipReason returns why fn won't be generated, along with the detail to
+// report, or "" if nothing rules it out before its parameters are read.
+func skipReason(fn *model.Callable, blacklist map[string] bool, deprecated bool) (string, string) {
+	switch {
+	case fn.Unintrospectable != "":
+		return SkipUnintrospectable, fn.Unintrospectable
+	case blacklist[fn.Symbol]:
+		return SkipBlacklisted, fn.Symbol
+	case fn.Deprecated && !deprecated:
+		return SkipDeprecated, since(fn.DeprecatedVersion)
+	}
+	return "", ""
+}
+
+// generated reports whether fn will be generated, given the blacklist of
+// its namespace. Name collisions aren't considered, since Names already
+// gives everything a name of its own.
+func generated(fn *model.Callable, blacklist map[string] bool, deprecated bool) bool {
+	if reason, _ := skipReason(fn, blacklist, deprecated); reason != "" {
+		return false
+	}
+	_, _, _, _, err := readParams(fn, nil)
+	return err == nil
+}
+
+func implementAll(def ObjectDefinition, face *model.Class, namespaces model.Namespaces, code *CodeFile, tmpl *template.Template) {
+	impl := NewObjectDefinition(face)
 	impl.ObjectName = def.ObjectName
 	err := tmpl.ExecuteTemplate(code, "object-implement", impl)
 	if err != nil {
 		fmt.Println(err.Error())
 	}
 
-	if hasParent(face) {
-		implementAll(def, face.GetParent(), code, tmpl)
+	if parent := namespaces.Class(face.Parent); parent != nil {
+		implementAll(def, parent, namespaces, code, tmpl)
 	}
 }
 
-func hasParent(info *BaseInfo) bool {
-	return info.GetName() != "Object" && !info.IsFundamental()
+/* -- Functions -- */
+
+// ProcessFunction writes a function that doesn't belong to any type.
+func ProcessFunction(function *model.Callable, namespace string, code *Code, tmpl *template.Template, blacklist *map[string] bool, overrides Overrides, deprecated bool, names *Names, docs *DocWriter, cov *coverage) {
+	qualified := model.QualifiedName(namespace, function.Name)
+	if reason, detail := skipReason(function, *blacklist, deprecated); reason != "" {
+		cov.skip("function", qualified, reason, detail)
+		return
+	}
+
+	goargs, gorets, cargs, crets, err := readParams(function, names)
+	if err != nil {
+		cov.skip("function", qualified, SkipUnsupported, err.Error())
+		return
+	}
+	cov.bind("function", qualified)
+
+	name := names.Function(namespace, function)
+	fn := FunctionDefinition{
+		Name:name,
+		ForGo:ArgsAndRets{Args:goargs, Rets:gorets},
+		ForC:ArgsAndRets{Args:cargs, Rets:crets},
+		Flags:function.Flags,
+		Function:function,
+		Doc:docs.Function(namespace, name, function),
+		Body:overrides.Body(function.Symbol),
+	}
+	fn.ArgMarshalBody, fn.RetMarshalBody = marshalBodies(cargs, gorets, tmpl)
+	err = tmpl.ExecuteTemplate(code.For(namespace, function.Version), "go-function", fn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 }
 
-/* -- Functions -- */
+// marshalBodies returns the code that converts a function's arguments to C
+// and its results back to Go.
+func marshalBodies(cargs, gorets []Parameter, tmpl *template.Template) (string, string) {
+	var args, rets bytes.Buffer
+	for _, param := range cargs {
+		switch param.Dir {
+			case model.In, model.InOut:
+				if param.Skip {
+					tmpl.ExecuteTemplate(&args, "c-decl", param)
+				} else {
+					tmpl.ExecuteTemplate(&args, "c-marshal", param)
+				}
+			case model.Out: tmpl.ExecuteTemplate(&args, "c-decl", param)
+		}
+	}
+	for _, ret := range gorets {
+		if ret.GoType == "error" {
+			tmpl.ExecuteTemplate(&rets, "go-error", ret)
+		} else {
+			tmpl.ExecuteTemplate(&rets, "go-marshal", ret)
+		}
+	}
+	return args.String(), rets.String()
+}
 
 type ArgsAndRets struct {
 	Args []Parameter
@@ -206,23 +296,26 @@ type ArgsAndRets struct {
 }
 
 type FunctionDefinition struct {
-	Name string
+	Name string // the Go name, i.e. "GetURI"
 	Owner *ObjectDefinition
+	Foreign *ObjectDefinition
 	ClassName string
 	ForGo ArgsAndRets
 	ForC ArgsAndRets
 	ArgMarshalBody string
 	RetMarshalBody string
-	Flags FunctionFlags
-	Info *BaseInfo
+	Flags model.FunctionFlags
+	Function *model.Callable
+	Doc string
+	Body string // hand-written, to use instead of calling C
 }
 
 func (def FunctionDefinition) GoName() string {
-	return CamelCase(def.Name)
+	return def.Name
 }
 
 func (def FunctionDefinition) CName() string {
-	return def.Info.GetSymbol()
+	return def.Function.Symbol
 }
 
 func (def FunctionDefinition) HasOwner() bool {
@@ -262,14 +355,19 @@ func (def FunctionDefinition) MarshaledValues() string {
 	index := 0
 	if (def.Owner != nil) {
 		result = make([]string, len(def.ForC.Args) + 1)
-		result[index] = "self." + def.Owner.CastFunc + "()"
+		switch ctype := cType(def.Function.InstanceCType, def.Owner.CType); ctype {
+			case "gpointer", "gconstpointer":
+				result[index] = "C." + ctype + "(self." + def.Owner.CastFunc + "())"
+			default:
+				result[index] = "(*C." + ctype + ")(self." + def.Owner.CastFunc + "())"
+		}
 		index++
 	} else {
 		result = make([]string, len(def.ForC.Args))
 	}
 	for i, param := range def.ForC.Args {
 		name := param.CName()
-		if param.Dir == Out || param.Dir == InOut {
+		if param.Dir == model.Out || param.Dir == model.InOut {
 			name = "&" + name
 		}
 		result[i + index] = name
@@ -283,13 +381,20 @@ func (def FunctionDefinition) CRet() Parameter {
 
 type Parameter struct {
 	Name string
-	Dir Direction
+	Arg string // for the result of an inout parameter, the name it's passed in as
+	Dir model.Direction
+	Skip bool
+	Nullable bool
+	Transfer model.Transfer
 	GoType string
 	CType string
-	Info *BaseInfo
+	Type *model.TypeRef
 }
 
 func (val Parameter) CName() string {
+	if val.Arg != "" {
+		return "c_" + val.Arg
+	}
 	return "c_" + val.Name
 }
 
@@ -297,87 +402,130 @@ func (val Parameter) IsPointer() bool {
 	// GErrors should always be handled as pointers
 	if val.CType == "GError" {
 		return true
-	} else if val.Info == nil {
+	} else if val.Type == nil || val.Type.Tag == model.VoidTag {
+		// gpointer is a pointer already
 		return false
 	}
-	return val.Info.GetType().IsPointer()
+	return val.Type.Pointer
+}
+
+// IsString is true of parameters that are copied between Go strings and
+// C ones.
+func (val Parameter) IsString() bool {
+	return val.GoType == "string"
+}
+
+func (val Parameter) IsBool() bool {
+	return val.GoType == "bool"
+}
+
+// Owned is true if the value changes hands: C frees strings passed to it,
+// and Go frees the ones it gets back.
+func (val Parameter) Owned() bool {
+	return val.Transfer == model.Everything
 }
 
-func returnsValue(typ *BaseInfo) bool {
+func returnsValue(typ *model.TypeRef) bool {
 	// a function doesn't return a value iff its tag is void and not a pointer
 	// a void pointer represents an arbitrary value
-	return typ.IsPointer() || typ.GetTag() != VoidTag
+	return typ.Pointer || typ.Tag != model.VoidTag
+}
+
+// marshalError describes a parameter that readParams can't handle.
+func marshalError(name string, typ *model.TypeRef) error {
+	desc := typ.Tag.String()
+	if typ.Tag == model.InterfaceTag {
+		desc = typ.Interface
+	}
+	return fmt.Errorf("couldn't marshal type %s of %s", desc, name)
 }
 
-func readParams(info *BaseInfo, flags FunctionFlags) ([]Parameter, []Parameter, []Parameter, []Parameter, error) {
+// cType returns the C type named by the c:type of a .gir file, or what it
+// points to, since the type tag alone can't tell gint from gint32. It falls
+// back to ctype when that isn't known.
+func cType(name, ctype string) string {
+	name = strings.TrimSpace(strings.TrimPrefix(name, "const "))
+	name = strings.TrimRight(name, "* ")
+	if name == "" || name == "void" || strings.Contains(name, " ") {
+		return ctype
+	}
+	return name
+}
+
+func readParams(fn *model.Callable, names *Names) ([]Parameter, []Parameter, []Parameter, []Parameter, error) {
 	goargList := list.New()
 	goretList := list.New()
 	cargList := list.New()
 	cretList := list.New()
-	marshalError := errors.New("couldn't marshal type")
 
-	ret := info.GetReturnType()
+	ret := fn.Return
 	if returnsValue(ret) {
 		var (
 			gotype, ctype string
 			ok bool
 		)
-		tag := ret.GetTag()
-		if tag == VoidTag && ret.IsPointer() {
+		tag := ret.Tag
+		if tag == model.VoidTag && ret.Pointer {
 			gotype = GoVoidPointer
 			ctype = CVoidPointer
 		} else {
 			if gotype, ok = TypeTagToGo[tag]; !ok {
-				return nil, nil, nil, nil, marshalError
+				return nil, nil, nil, nil, marshalError("return value", ret)
 			}
 			if ctype, ok = TypeTagToC[tag]; !ok {
-				return nil, nil, nil, nil, marshalError
+				return nil, nil, nil, nil, marshalError("return value", ret)
 			}
 		}
-		cretList.PushBack(Parameter{Name:"retval", Dir:Out, GoType:gotype, CType:ctype, Info:nil})
+		p := Parameter{Name:"retval", Dir:model.Out, Nullable:fn.MayReturnNull, Transfer:fn.ReturnTransfer, GoType:gotype, CType:cType(ret.CType, ctype), Type:nil}
+		cretList.PushBack(p)
+		goretList.PushBack(p)
 	}
 
-	n := info.GetNArgs()
-	for i := 0; i < n; i++ {
-		param := info.GetArg(i)
-		dir := param.GetDirection()
-		name := param.GetName()
+	goNames := names.Params(fn)
+	for i, param := range fn.Params {
+		dir := param.Direction
+		name := goNames[i]
 
 		var (
 			gotype, ctype string
 			ok bool
 		)
-		tag := param.GetType().GetTag()
-		if tag == VoidTag && param.GetType().IsPointer() {
+		tag := param.Type.Tag
+		if tag == model.VoidTag && param.Type.Pointer {
 			gotype = GoVoidPointer
 			ctype = CVoidPointer
 		} else {
 			if gotype, ok = TypeTagToGo[tag]; !ok {
-				return nil, nil, nil, nil, marshalError
+				return nil, nil, nil, nil, marshalError(name, param.Type)
 			}
 			if ctype, ok = TypeTagToC[tag]; !ok {
-				return nil, nil, nil, nil, marshalError
-			}
-
-			// check if it's a quark
-			// TODO: there HAS to be a better way than this...
-			if tag == Uint32Tag && name == "quark" {
-				ctype = "GQuark"
+				return nil, nil, nil, nil, marshalError(name, param.Type)
 			}
 		}
 
-		p := Parameter{Name:name, Dir:dir, GoType:gotype, CType:ctype, Info:param}
+		p := Parameter{Name:name, Dir:dir, Skip:param.Skip, Nullable:param.Nullable, Transfer:param.Transfer, GoType:gotype, CType:cType(param.Type.CType, ctype), Type:param.Type}
 		cargList.PushBack(p)
-		if dir == In || dir == InOut {
+		if param.Skip {
+			// still passed to C, but as a zero value
+			continue
+		}
+		if dir == model.In || dir == model.InOut {
 			goargList.PushBack(p)
 		}
-		if dir == Out || dir == InOut {
+		if dir == model.InOut {
+			// the result can't have the same name as the argument
+			p.Arg = p.Name
+			p.Name += "Out"
+		}
+		if dir == model.Out || dir == model.InOut {
 			goretList.PushBack(p)
 		}
 	}
 
-	if flags.Throws {
-		goretList.PushBack(Parameter{Name:"error", Dir:Out, GoType:"error", CType:"GError", Info:nil})
+	if fn.Flags.Throws {
+		p := Parameter{Name:"err", Dir:model.Out, GoType:"error", CType:"GError", Type:nil}
+		cargList.PushBack(p)
+		goretList.PushBack(p)
 	}
 
 	goArgs := make([]Parameter, goargList.Len())
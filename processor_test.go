@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// method returns a method of a class in a hand-built model that takes
+// nothing but its instance and returns nothing.
+func method(name, symbol string) *model.Callable {
+	return &model.Callable{
+		Name:   name,
+		Symbol: symbol,
+		Flags:  model.FunctionFlags{IsMethod: true},
+		Return: &model.TypeRef{Tag: model.VoidTag},
+	}
+}
+
+// TestProcessObject renders a class built by hand, which inherits from a
+// class in another namespace, and checks which of its methods and the
+// inherited ones are generated and what's reported for the rest.
+func TestProcessObject(t *testing.T) {
+	deprecated := method("reset", "foo_widget_reset")
+	deprecated.Deprecated, deprecated.DeprecatedVersion = true, "1.2"
+	varargs := method("set_names", "foo_widget_set_names")
+	varargs.Unintrospectable = "takes varargs"
+	unsupported := method("fill", "foo_widget_fill")
+	unsupported.Params = []*model.Param{{Name: "values", Type: &model.TypeRef{Tag: model.ArrayTag, Pointer: true}}}
+	widget := &model.Class{
+		Name:      "Widget",
+		Namespace: "Foo",
+		CType:     "FooWidget",
+		Parent:    "GObject.Object",
+		Methods: []*model.Callable{
+			method("show", "foo_widget_show"),
+			method("hide", "foo_widget_hide"),
+			method("ref", "foo_widget_ref"),
+			deprecated,
+			varargs,
+			unsupported,
+		},
+	}
+	object := &model.Class{
+		Name:      "Object",
+		Namespace: "GObject",
+		CType:     "GObject",
+		Methods: []*model.Callable{
+			method("ref", "g_object_ref"),
+			method("unref", "g_object_unref"),
+			method("compat_control", "g_object_compat_control"),
+		},
+	}
+	namespaces := model.Namespaces{
+		"GObject": {Name: "GObject", Version: "2.0", Classes: []*model.Class{object}},
+		"Foo":     {Name: "Foo", Version: "1.0", Includes: []string{"GObject-2.0"}, Classes: []*model.Class{widget}},
+	}
+	// the blacklist of GObject applies to the methods Widget inherits
+	blacklists := map[string]map[string]bool{
+		"GObject": {"g_object_compat_control": true},
+		"Foo":     {"foo_widget_hide": true},
+	}
+
+	tmpl, err := template.New("go-gi").ParseFS(Assets("", "snippets"), "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := NewNames(namespaces, nil, nil)
+	docs := NewDocWriter(namespaces, blacklists, false, "gi", names)
+	code := NewCode(NewVersionGates(namespaces))
+	exists := make(map[string]bool)
+	var cov coverage
+	ProcessObject(widget, namespaces, code, tmpl, &exists, blacklists, nil, false, names, docs, &cov)
+
+	wantBound := []Symbol{
+		{Kind: "class", Name: "Foo.Widget"},
+		{Kind: "method", Name: "Foo.Widget.ref"},
+		{Kind: "method", Name: "Foo.Widget.show"},
+	}
+	wantSkipped := []Symbol{
+		{Kind: "method", Name: "Foo.Widget.fill", Reason: SkipUnsupported},
+		{Kind: "method", Name: "Foo.Widget.hide", Reason: SkipBlacklisted, Detail: "foo_widget_hide"},
+		{Kind: "method", Name: "Foo.Widget.reset", Reason: SkipDeprecated, Detail: "since 1.2"},
+		{Kind: "method", Name: "Foo.Widget.set_names", Reason: SkipUnintrospectable, Detail: "takes varargs"},
+	}
+	sortSymbols(cov.bound)
+	sortSymbols(cov.skipped)
+	if !reflect.DeepEqual(cov.bound, wantBound) {
+		t.Errorf("bound %+v, want %+v", cov.bound, wantBound)
+	}
+	// the detail of unsupported types is the marshalling error
+	if len(cov.skipped) > 0 && cov.skipped[0].Reason == SkipUnsupported {
+		cov.skipped[0].Detail = ""
+	}
+	if !reflect.DeepEqual(cov.skipped, wantSkipped) {
+		t.Errorf("skipped %+v, want %+v", cov.skipped, wantSkipped)
+	}
+
+	if len(code.Files) != 1 {
+		t.Errorf("generated %d files, want 1", len(code.Files))
+	}
+	out := code.Tagged("")
+	generated := out.String()
+	for _, want := range []string{
+		"func (self *Widget) Show() ()",
+		"C.foo_widget_show(",
+		"func (self *Widget) Unref() ()",
+		"(*gobject.Object)(self.AsGObjectObject()).Unref()",
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code doesn't contain %q:\n%s", want, generated)
+		}
+	}
+	// Widget's own ref hides the inherited one
+	if n := strings.Count(generated, "func (self *Widget) Ref() ()"); n != 1 {
+		t.Errorf("Ref is defined %d times, want 1", n)
+	}
+	for _, unwanted := range []string{"Hide", "CompatControl", "Reset", "SetNames", "Fill"} {
+		if strings.Contains(generated, unwanted) {
+			t.Errorf("generated code contains %s, which should have been skipped", unwanted)
+		}
+	}
+	if !out.Imports["gobject"] {
+		t.Error("the gobject package isn't imported for the inherited methods")
+	}
+}
@@ -0,0 +1,7 @@
+package glib
+
+// #cgo pkg-config: glib-2.0
+// #cgo CFLAGS: -Wno-error
+// #include <glib.h>
+import "C"
+
@@ -0,0 +1,50 @@
+package gobject
+
+// #cgo pkg-config: gobject-2.0
+// #cgo CFLAGS: -Wno-error
+// #include <glib-object.h>
+import "C"
+import "unsafe"
+
+// Object wraps GObject.
+type Object C.GObject
+
+type ObjectLike interface {
+	AsGObjectObject() unsafe.Pointer
+}
+
+func (self *Object) AsGObjectObject() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+// Ref wraps g_object_ref().
+func (self *Object) Ref() (retval unsafe.Pointer) {
+	return privObjectRef(self)
+}
+
+func privObjectRef(self ObjectLike) (retval unsafe.Pointer) {
+	c_retval := C.g_object_ref(C.gpointer(self.AsGObjectObject()))
+	retval = unsafe.Pointer(c_retval)
+	return
+}
+
+// RefSink wraps g_object_ref_sink().
+func (self *Object) RefSink() (retval unsafe.Pointer) {
+	return privObjectRefSink(self)
+}
+
+func privObjectRefSink(self ObjectLike) (retval unsafe.Pointer) {
+	c_retval := C.g_object_ref_sink(C.gpointer(self.AsGObjectObject()))
+	retval = unsafe.Pointer(c_retval)
+	return
+}
+
+// Unref wraps g_object_unref().
+func (self *Object) Unref() () {
+	privObjectUnref(self)
+}
+
+func privObjectUnref(self ObjectLike) () {
+	C.g_object_unref(C.gpointer(self.AsGObjectObject()))
+}
+
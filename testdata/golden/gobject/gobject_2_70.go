@@ -0,0 +1,21 @@
+//go:build !gobject_2_10
+
+package gobject
+
+// #cgo pkg-config: gobject-2.0
+// #cgo CFLAGS: -Wno-error
+// #include <glib-object.h>
+import "C"
+import "unsafe"
+
+// TakeRef wraps g_object_take_ref().
+func (self *Object) TakeRef() (retval unsafe.Pointer) {
+	return privObjectTakeRef(self)
+}
+
+func privObjectTakeRef(self ObjectLike) (retval unsafe.Pointer) {
+	c_retval := C.g_object_take_ref(C.gpointer(self.AsGObjectObject()))
+	retval = unsafe.Pointer(c_retval)
+	return
+}
+
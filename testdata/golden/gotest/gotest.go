@@ -0,0 +1,285 @@
+package gotest
+
+// #cgo LDFLAGS: -lgotest
+// #cgo CFLAGS: -Wno-error
+// #include <gotest.h>
+import "C"
+import "unsafe"
+
+import "errors"
+import "gi/gobject"
+
+// Color wraps GoTestColor.
+//
+// A plain enumeration.
+type Color C.GoTestColor
+const (
+	// red
+	ColorRed Color = 0
+	// green
+	ColorGreen Color = 1
+	// blue
+	ColorBlue Color = 2
+)
+
+// ThingFlags wraps GoTestThingFlags.
+//
+// What a thing has, or'd together.
+type ThingFlags C.GoTestThingFlags
+const (
+	// nothing
+	ThingFlagsNone ThingFlags = 0
+	// it has a name
+	ThingFlagsNamed ThingFlags = 1
+	// it has a size
+	ThingFlagsSized ThingFlags = 2
+)
+
+// Thing wraps GoTestThing.
+//
+// Something with a name, which [SubThing] builds on.
+type Thing C.GoTestThing
+
+type ThingLike interface {
+	AsGoTestThing() unsafe.Pointer
+}
+
+func (self *Thing) AsGoTestThing() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+func (self *Thing) AsGObjectObject() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+// Add wraps go_test_thing_add().
+//
+//   - a: a number
+//   - b: another number
+//
+// Returns the sum of a and b.
+func (self *Thing) Add(a int32, b int32) (retval int32) {
+	return privThingAdd(self, a, b)
+}
+
+func privThingAdd(self ThingLike, a int32, b int32) (retval int32) {
+	c_a := C.gint(a)
+	c_b := C.gint(b)
+	c_retval := C.go_test_thing_add((*C.GoTestThing)(self.AsGoTestThing()), c_a, c_b)
+	retval = int32(c_retval)
+	return
+}
+
+// DupName wraps go_test_thing_dup_name().
+//
+// Returns a copy of the name of self.
+func (self *Thing) DupName() (retval string) {
+	return privThingDupName(self)
+}
+
+func privThingDupName(self ThingLike) (retval string) {
+	c_retval := C.go_test_thing_dup_name((*C.GoTestThing)(self.AsGoTestThing()))
+	retval = C.GoString((*C.char)(unsafe.Pointer(c_retval)))
+	C.g_free(C.gpointer(unsafe.Pointer(c_retval)))
+	return
+}
+
+// GetCount wraps go_test_thing_get_count().
+//
+//   - count: where to store how many names self has
+func (self *Thing) GetCount() (count int32) {
+	return privThingGetCount(self)
+}
+
+func privThingGetCount(self ThingLike) (count int32) {
+	var c_count C.gint
+	C.go_test_thing_get_count((*C.GoTestThing)(self.AsGoTestThing()), &c_count)
+	count = int32(c_count)
+	return
+}
+
+// Name wraps go_test_thing_get_name().
+//
+// Returns the name, if it has one.
+func (self *Thing) Name() (retval string) {
+	return privThingName(self)
+}
+
+func privThingName(self ThingLike) (retval string) {
+	c_retval := C.go_test_thing_get_name((*C.GoTestThing)(self.AsGoTestThing()))
+	retval = C.GoString((*C.char)(unsafe.Pointer(c_retval)))
+	return
+}
+
+// GetSize wraps go_test_thing_get_size().
+//
+// Gets both dimensions at once, unlike go_test_thing_get_width(). A thing
+// made with go_test_thing_new() has neither.
+//
+//   - width: where to put the width
+//   - height: where to put the height
+func (self *Thing) GetSize() (width int32, height int32) {
+	return privThingGetSize(self)
+}
+
+func privThingGetSize(self ThingLike) (width int32, height int32) {
+	var c_width C.gint
+	var c_height C.gint
+	C.go_test_thing_get_size((*C.GoTestThing)(self.AsGoTestThing()), &c_width, &c_height)
+	width = int32(c_width)
+	height = int32(c_height)
+	return
+}
+
+// HasTag wraps go_test_thing_has_tag().
+//
+//   - tag: a quark
+//
+// Returns whether tag is the quark of "thing".
+func (self *Thing) HasTag(tag uint32) (retval bool) {
+	return privThingHasTag(self, tag)
+}
+
+func privThingHasTag(self ThingLike, tag uint32) (retval bool) {
+	c_tag := C.GQuark(tag)
+	c_retval := C.go_test_thing_has_tag((*C.GoTestThing)(self.AsGoTestThing()), c_tag)
+	retval = c_retval != 0
+	return
+}
+
+// Load wraps go_test_thing_load().
+//
+//   - path: a file name
+//
+// Returns true if path isn't empty.
+func (self *Thing) Load(path string) (retval bool, err error) {
+	return privThingLoad(self, path)
+}
+
+func privThingLoad(self ThingLike, path string) (retval bool, err error) {
+	c_path := (*C.gchar)(unsafe.Pointer(C.CString(path)))
+	defer C.g_free(C.gpointer(unsafe.Pointer(c_path)))
+	var c_err *C.GError
+	c_retval := C.go_test_thing_load((*C.GoTestThing)(self.AsGoTestThing()), c_path, &c_err)
+	retval = c_retval != 0
+	if c_err != nil {
+		err = errors.New(C.GoString((*C.char)(unsafe.Pointer(c_err.message))))
+		C.g_error_free(c_err)
+	}
+	return
+}
+
+// GetSize2 wraps go_test_thing_measure().
+//
+// Gets the width self would have if it were named string_, and how many
+// lines it would take up in n_lines, which is always 1.
+//
+//   - string_: a name
+//   - width: where to put the width
+func (self *Thing) GetSize2(string_ string) (width int32) {
+	return privThingGetSize2(self, string_)
+}
+
+func privThingGetSize2(self ThingLike, string_ string) (width int32) {
+	c_string_ := (*C.gchar)(unsafe.Pointer(C.CString(string_)))
+	defer C.g_free(C.gpointer(unsafe.Pointer(c_string_)))
+	var c_width C.gint
+	var c_nLines C.gint
+	C.go_test_thing_measure((*C.GoTestThing)(self.AsGoTestThing()), c_string_, &c_width, &c_nLines)
+	width = int32(c_width)
+	return
+}
+
+// Save wraps go_test_thing_save().
+//
+//   - path: a file name
+//
+// Returns true if path isn't empty.
+func (self *Thing) Save(path string) (retval bool, err error) {
+	return privThingSave(self, path)
+}
+
+func privThingSave(self ThingLike, path string) (retval bool, err error) {
+	c_path := (*C.gchar)(unsafe.Pointer(C.CString(path)))
+	defer C.g_free(C.gpointer(unsafe.Pointer(c_path)))
+	var c_err *C.GError
+	c_retval := C.go_test_thing_save((*C.GoTestThing)(self.AsGoTestThing()), c_path, &c_err)
+	retval = c_retval != 0
+	if c_err != nil {
+		err = errors.New(C.GoString((*C.char)(unsafe.Pointer(c_err.message))))
+		C.g_error_free(c_err)
+	}
+	return
+}
+
+// Scale wraps go_test_thing_scale().
+//
+//   - factor: how much to scale by
+//
+// Returns factor doubled.
+func (self *Thing) Scale(factor float64) (retval float64) {
+	return privThingScale(self, factor)
+}
+
+func privThingScale(self ThingLike, factor float64) (retval float64) {
+	c_factor := C.gdouble(factor)
+	c_retval := C.go_test_thing_scale((*C.GoTestThing)(self.AsGoTestThing()), c_factor)
+	retval = float64(c_retval)
+	return
+}
+
+// SetName wraps go_test_thing_set_name().
+//
+// Names self, or clears its name if name is nil. The name can be read
+// back with [Thing.Name].
+//
+//   - name: the new name
+func (self *Thing) SetName(name string) () {
+	privThingSetName(self, name)
+}
+
+func privThingSetName(self ThingLike, name string) () {
+	var c_name *C.gchar
+	if name != "" {
+		c_name = (*C.gchar)(unsafe.Pointer(C.CString(name)))
+		defer C.g_free(C.gpointer(unsafe.Pointer(c_name)))
+	}
+	C.go_test_thing_set_name((*C.GoTestThing)(self.AsGoTestThing()), c_name)
+}
+
+// SetURI wraps go_test_thing_set_uri().
+//
+// Points self at uri, which holds something of the MIME type type_.
+//
+//   - uri: a URI
+//   - type_: a MIME type
+func (self *Thing) SetURI(uri string, type_ string) () {
+	privThingSetURI(self, uri, type_)
+}
+
+func privThingSetURI(self ThingLike, uri string, type_ string) () {
+	c_uri := (*C.gchar)(unsafe.Pointer(C.CString(uri)))
+	defer C.g_free(C.gpointer(unsafe.Pointer(c_uri)))
+	var c_type_ *C.gchar
+	if type_ != "" {
+		c_type_ = (*C.gchar)(unsafe.Pointer(C.CString(type_)))
+		defer C.g_free(C.gpointer(unsafe.Pointer(c_type_)))
+	}
+	C.go_test_thing_set_uri((*C.GoTestThing)(self.AsGoTestThing()), c_uri, c_type_)
+}
+
+// Ref wraps g_object_ref().
+func (self *Thing) Ref() (retval unsafe.Pointer) {
+	return (*gobject.Object)(self.AsGObjectObject()).Ref()
+}
+
+// RefSink wraps g_object_ref_sink().
+func (self *Thing) RefSink() (retval unsafe.Pointer) {
+	return (*gobject.Object)(self.AsGObjectObject()).RefSink()
+}
+
+// Unref wraps g_object_unref().
+func (self *Thing) Unref() () {
+	(*gobject.Object)(self.AsGObjectObject()).Unref()
+}
+
@@ -0,0 +1,168 @@
+//go:build !gotest_1_2
+
+package gotest
+
+// #cgo LDFLAGS: -lgotest
+// #cgo CFLAGS: -Wno-error
+// #include <gotest.h>
+import "C"
+import "unsafe"
+
+import "gi/gobject"
+
+// SubThing wraps GoTestSubThing.
+//
+// A thing that can be reset.
+type SubThing C.GoTestSubThing
+
+type SubThingLike interface {
+	AsGoTestSubThing() unsafe.Pointer
+}
+
+func (self *SubThing) AsGoTestSubThing() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+func (self *SubThing) AsGoTestThing() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+func (self *SubThing) AsGObjectObject() unsafe.Pointer {
+	return unsafe.Pointer(self)
+}
+
+// Reset wraps go_test_sub_thing_reset().
+//
+// Clears the name.
+func (self *SubThing) Reset() () {
+	privSubThingReset(self)
+}
+
+func privSubThingReset(self SubThingLike) () {
+	C.go_test_thing_set_name((*C.GoTestThing)(self.AsGoTestSubThing()), nil)
+}
+
+// Add wraps go_test_thing_add().
+//
+//   - a: a number
+//   - b: another number
+//
+// Returns the sum of a and b.
+func (self *SubThing) Add(a int32, b int32) (retval int32) {
+	return privThingAdd(self, a, b)
+}
+
+// DupName wraps go_test_thing_dup_name().
+//
+// Returns a copy of the name of self.
+func (self *SubThing) DupName() (retval string) {
+	return privThingDupName(self)
+}
+
+// GetCount wraps go_test_thing_get_count().
+//
+//   - count: where to store how many names self has
+func (self *SubThing) GetCount() (count int32) {
+	return privThingGetCount(self)
+}
+
+// Name wraps go_test_thing_get_name().
+//
+// Returns the name, if it has one.
+func (self *SubThing) Name() (retval string) {
+	return privThingName(self)
+}
+
+// GetSize wraps go_test_thing_get_size().
+//
+// Gets both dimensions at once, unlike go_test_thing_get_width(). A thing
+// made with go_test_thing_new() has neither.
+//
+//   - width: where to put the width
+//   - height: where to put the height
+func (self *SubThing) GetSize() (width int32, height int32) {
+	return privThingGetSize(self)
+}
+
+// HasTag wraps go_test_thing_has_tag().
+//
+//   - tag: a quark
+//
+// Returns whether tag is the quark of "thing".
+func (self *SubThing) HasTag(tag uint32) (retval bool) {
+	return privThingHasTag(self, tag)
+}
+
+// Load wraps go_test_thing_load().
+//
+//   - path: a file name
+//
+// Returns true if path isn't empty.
+func (self *SubThing) Load(path string) (retval bool, err error) {
+	return privThingLoad(self, path)
+}
+
+// GetSize2 wraps go_test_thing_measure().
+//
+// Gets the width self would have if it were named string_, and how many
+// lines it would take up in n_lines, which is always 1.
+//
+//   - string_: a name
+//   - width: where to put the width
+func (self *SubThing) GetSize2(string_ string) (width int32) {
+	return privThingGetSize2(self, string_)
+}
+
+// Save wraps go_test_thing_save().
+//
+//   - path: a file name
+//
+// Returns true if path isn't empty.
+func (self *SubThing) Save(path string) (retval bool, err error) {
+	return privThingSave(self, path)
+}
+
+// Scale wraps go_test_thing_scale().
+//
+//   - factor: how much to scale by
+//
+// Returns factor doubled.
+func (self *SubThing) Scale(factor float64) (retval float64) {
+	return privThingScale(self, factor)
+}
+
+// SetName wraps go_test_thing_set_name().
+//
+// Names self, or clears its name if name is nil. The name can be read
+// back with [Thing.Name].
+//
+//   - name: the new name
+func (self *SubThing) SetName(name string) () {
+	privThingSetName(self, name)
+}
+
+// SetURI wraps go_test_thing_set_uri().
+//
+// Points self at uri, which holds something of the MIME type type_.
+//
+//   - uri: a URI
+//   - type_: a MIME type
+func (self *SubThing) SetURI(uri string, type_ string) () {
+	privThingSetURI(self, uri, type_)
+}
+
+// Ref wraps g_object_ref().
+func (self *SubThing) Ref() (retval unsafe.Pointer) {
+	return (*gobject.Object)(self.AsGObjectObject()).Ref()
+}
+
+// RefSink wraps g_object_ref_sink().
+func (self *SubThing) RefSink() (retval unsafe.Pointer) {
+	return (*gobject.Object)(self.AsGObjectObject()).RefSink()
+}
+
+// Unref wraps g_object_unref().
+func (self *SubThing) Unref() () {
+	(*gobject.Object)(self.AsGObjectObject()).Unref()
+}
+
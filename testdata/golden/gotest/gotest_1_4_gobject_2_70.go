@@ -0,0 +1,17 @@
+//go:build !gotest_1_2 && !gobject_2_10
+
+package gotest
+
+// #cgo LDFLAGS: -lgotest
+// #cgo CFLAGS: -Wno-error
+// #include <gotest.h>
+import "C"
+import "unsafe"
+
+import "gi/gobject"
+
+// TakeRef wraps g_object_take_ref().
+func (self *SubThing) TakeRef() (retval unsafe.Pointer) {
+	return (*gobject.Object)(self.AsGObjectObject()).TakeRef()
+}
+
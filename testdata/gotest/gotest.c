@@ -0,0 +1,276 @@
+#include <string.h>
+
+#include "gotest.h"
+
+G_DEFINE_TYPE (GoTestThing, go_test_thing, G_TYPE_OBJECT)
+
+static void
+go_test_thing_class_init (GoTestThingClass *klass)
+{
+}
+
+static void
+go_test_thing_init (GoTestThing *self)
+{
+}
+
+/**
+ * go_test_thing_new:
+ *
+ * Returns: (transfer full): a new thing
+ */
+GoTestThing *
+go_test_thing_new (void)
+{
+	return g_object_new (GO_TEST_TYPE_THING, NULL);
+}
+
+/**
+ * go_test_thing_add:
+ * @self: a thing
+ * @a: a number
+ * @b: another number
+ *
+ * Returns: the sum of @a and @b
+ */
+gint
+go_test_thing_add (GoTestThing *self, gint a, gint b)
+{
+	return a + b;
+}
+
+/**
+ * go_test_thing_scale:
+ * @self: a thing
+ * @factor: how much to scale by
+ *
+ * Returns: @factor doubled
+ */
+gdouble
+go_test_thing_scale (GoTestThing *self, gdouble factor)
+{
+	return factor * 2;
+}
+
+/**
+ * go_test_thing_set_name:
+ * @self: a thing
+ * @name: (nullable): the new name
+ *
+ * Names @self, or clears its name if @name is %NULL. The name can be read
+ * back with go_test_thing_get_name().
+ */
+void
+go_test_thing_set_name (GoTestThing *self, const gchar *name)
+{
+	g_object_set_data_full (G_OBJECT (self), "name", g_strdup (name), g_free);
+}
+
+/**
+ * go_test_thing_set_names:
+ * @self: a thing
+ * @first_name: the first name
+ * @...: more names, ending with %NULL
+ *
+ * Names @self after the last of the names given.
+ */
+void
+go_test_thing_set_names (GoTestThing *self, const gchar *first_name, ...)
+{
+	const gchar *name = first_name;
+	const gchar *next;
+	va_list args;
+
+	va_start (args, first_name);
+	while ((next = va_arg (args, const gchar *)) != NULL)
+		name = next;
+	va_end (args);
+	go_test_thing_set_name (self, name);
+}
+
+/**
+ * go_test_thing_set_uri:
+ * @self: a thing
+ * @uri: a URI
+ * @type: a MIME type
+ *
+ * Points @self at @uri, which holds something of the MIME type @type.
+ */
+void
+go_test_thing_set_uri (GoTestThing *self, const gchar *uri, const gchar *type)
+{
+	g_object_set_data_full (G_OBJECT (self), "uri", g_strdup (uri), g_free);
+	g_object_set_data_full (G_OBJECT (self), "type", g_strdup (type), g_free);
+}
+
+/**
+ * go_test_thing_get_name:
+ * @self: a thing
+ *
+ * Returns: (nullable): the name, if it has one
+ */
+const gchar *
+go_test_thing_get_name (GoTestThing *self)
+{
+	return g_object_get_data (G_OBJECT (self), "name");
+}
+
+/**
+ * go_test_thing_get_size:
+ * @self: a thing
+ * @width: (out): where to put the width
+ * @height: (out): where to put the height
+ *
+ * Gets both dimensions at once, unlike go_test_thing_get_width(). A thing
+ * made with [ctor@GoTest.Thing.new] has neither.
+ */
+void
+go_test_thing_get_size (GoTestThing *self, gint *width, gint *height)
+{
+	const gchar *name = go_test_thing_get_name (self);
+	*width = name ? strlen (name) : 0;
+	*height = 1;
+}
+
+/**
+ * go_test_thing_measure:
+ * @self: a thing
+ * @string: a name
+ * @width: (out): where to put the width
+ * @n_lines: (out): where to put the number of lines
+ *
+ * Gets the width @self would have if it were named @string, and how many
+ * lines it would take up in @n_lines, which is always 1.
+ */
+void
+go_test_thing_measure (GoTestThing *self, const gchar *string, gint *width, gint *n_lines)
+{
+	*width = string ? strlen (string) : 0;
+	*n_lines = 1;
+}
+
+/**
+ * go_test_thing_get_width:
+ * @self: a thing
+ *
+ * Returns: the width
+ *
+ * Deprecated: 1.2: Use go_test_thing_get_size() instead.
+ */
+gint
+go_test_thing_get_width (GoTestThing *self)
+{
+	gint width, height;
+
+	go_test_thing_get_size (self, &width, &height);
+	return width;
+}
+
+/**
+ * go_test_thing_dup_name:
+ * @self: a thing
+ *
+ * Returns: (transfer none): a copy of the name of @self
+ */
+gchar *
+go_test_thing_dup_name (GoTestThing *self)
+{
+	return g_strdup (go_test_thing_get_name (self));
+}
+
+/**
+ * go_test_thing_get_count:
+ * @self: a thing
+ * @count: (in): where to store how many names @self has
+ */
+void
+go_test_thing_get_count (GoTestThing *self, gint *count)
+{
+	*count = go_test_thing_get_name (self) ? 1 : 0;
+}
+
+/**
+ * go_test_thing_has_tag:
+ * @self: a thing
+ * @tag: a quark
+ *
+ * Returns: whether @tag is the quark of "thing"
+ */
+gboolean
+go_test_thing_has_tag (GoTestThing *self, GQuark tag)
+{
+	return tag == g_quark_from_static_string ("thing");
+}
+
+/**
+ * go_test_thing_save:
+ * @self: a thing
+ * @path: a file name
+ * @error: (out): return location for a #GError
+ *
+ * Returns: %TRUE if @path isn't empty
+ */
+gboolean
+go_test_thing_save (GoTestThing *self, const gchar *path, GError **error)
+{
+	return go_test_thing_load (self, path, error);
+}
+
+/**
+ * go_test_thing_load:
+ * @self: a thing
+ * @path: a file name
+ * @error: return location for a #GError
+ *
+ * Returns: %TRUE if @path isn't empty
+ *
+ * Since: 1.2
+ */
+gboolean
+go_test_thing_load (GoTestThing *self, const gchar *path, GError **error)
+{
+	if (path == NULL || *path == '\0') {
+		g_set_error_literal (error, G_FILE_ERROR, G_FILE_ERROR_NOENT, "no path given");
+		return FALSE;
+	}
+	return TRUE;
+}
+
+struct _GoTestSubThing {
+	GoTestThing parent_instance;
+};
+
+G_DEFINE_TYPE (GoTestSubThing, go_test_sub_thing, GO_TEST_TYPE_THING)
+
+static void
+go_test_sub_thing_class_init (GoTestSubThingClass *klass)
+{
+}
+
+static void
+go_test_sub_thing_init (GoTestSubThing *self)
+{
+}
+
+/**
+ * go_test_sub_thing_new:
+ *
+ * Returns: (transfer full): a new sub-thing
+ */
+GoTestSubThing *
+go_test_sub_thing_new (void)
+{
+	return g_object_new (GO_TEST_TYPE_SUB_THING, NULL);
+}
+
+/**
+ * go_test_sub_thing_reset:
+ * @self: a sub-thing
+ *
+ * Clears the name.
+ */
+void
+go_test_sub_thing_reset (GoTestSubThing *self)
+{
+	go_test_thing_set_name (GO_TEST_THING (self), NULL);
+}
@@ -0,0 +1,82 @@
+/* A tiny GObject library for checking the generator against. */
+
+#ifndef GO_TEST_H
+#define GO_TEST_H
+
+#include <glib-object.h>
+
+G_BEGIN_DECLS
+
+/**
+ * GoTestColor:
+ * @GO_TEST_COLOR_RED: red
+ * @GO_TEST_COLOR_GREEN: green
+ * @GO_TEST_COLOR_BLUE: blue
+ *
+ * A plain enumeration.
+ */
+typedef enum {
+	GO_TEST_COLOR_RED,
+	GO_TEST_COLOR_GREEN,
+	GO_TEST_COLOR_BLUE
+} GoTestColor;
+
+/**
+ * GoTestThingFlags:
+ * @GO_TEST_THING_FLAGS_NONE: nothing
+ * @GO_TEST_THING_FLAGS_NAMED: it has a name
+ * @GO_TEST_THING_FLAGS_SIZED: it has a size
+ *
+ * What a thing has, or'd together.
+ */
+typedef enum /*< flags >*/ {
+	GO_TEST_THING_FLAGS_NONE = 0,
+	GO_TEST_THING_FLAGS_NAMED = 1 << 0,
+	GO_TEST_THING_FLAGS_SIZED = 1 << 1
+} GoTestThingFlags;
+
+/**
+ * GoTestThing:
+ *
+ * Something with a name, which #GoTestSubThing builds on.
+ */
+#define GO_TEST_TYPE_THING (go_test_thing_get_type ())
+G_DECLARE_DERIVABLE_TYPE (GoTestThing, go_test_thing, GO_TEST, THING, GObject)
+
+struct _GoTestThingClass {
+	GObjectClass parent_class;
+};
+
+GoTestThing *go_test_thing_new (void);
+gint go_test_thing_add (GoTestThing *self, gint a, gint b);
+gchar *go_test_thing_dup_name (GoTestThing *self);
+void go_test_thing_get_count (GoTestThing *self, gint *count);
+gboolean go_test_thing_save (GoTestThing *self, const gchar *path, GError **error);
+gdouble go_test_thing_scale (GoTestThing *self, gdouble factor);
+void go_test_thing_set_name (GoTestThing *self, const gchar *name);
+void go_test_thing_set_names (GoTestThing *self, const gchar *first_name, ...) G_GNUC_NULL_TERMINATED;
+void go_test_thing_set_uri (GoTestThing *self, const gchar *uri, const gchar *type);
+const gchar *go_test_thing_get_name (GoTestThing *self);
+void go_test_thing_get_size (GoTestThing *self, gint *width, gint *height);
+G_DEPRECATED_FOR (go_test_thing_get_size)
+gint go_test_thing_get_width (GoTestThing *self);
+void go_test_thing_measure (GoTestThing *self, const gchar *string, gint *width, gint *n_lines);
+gboolean go_test_thing_has_tag (GoTestThing *self, GQuark tag);
+gboolean go_test_thing_load (GoTestThing *self, const gchar *path, GError **error);
+
+/**
+ * GoTestSubThing:
+ *
+ * A thing that can be reset.
+ *
+ * Since: 1.4
+ */
+#define GO_TEST_TYPE_SUB_THING (go_test_sub_thing_get_type ())
+G_DECLARE_FINAL_TYPE (GoTestSubThing, go_test_sub_thing, GO_TEST, SUB_THING, GoTestThing)
+
+GoTestSubThing *go_test_sub_thing_new (void);
+void go_test_sub_thing_reset (GoTestSubThing *self);
+
+G_END_DECLS
+
+#endif
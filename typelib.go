@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dradtke/go-gi/gi"
+	"github.com/dradtke/go-gi/model"
+)
+
+// TypelibLoader reads namespaces from the compiled typelibs that
+// libgirepository can find on this machine. It's safe to use from several
+// goroutines at once. Typelibs say whether something is deprecated, which
+// is all that decides whether it's generated, but not since when or why,
+// and not which version anything was added in, so nothing read from one
+// is gated behind build tags; model.CopyDocs fills those in from the .gir
+// file.
+type TypelibLoader struct {
+	Repository *gi.Repository // nil means gi.DefaultRepository()
+}
+
+func (loader TypelibLoader) Load(namespace, version string) (*model.Namespace, error) {
+	repo := loader.Repository
+	if repo == nil {
+		repo = gi.DefaultRepository()
+	}
+	if _, err := repo.Require(namespace, version); err != nil {
+		return nil, err
+	}
+
+	ns := &model.Namespace{
+		Name:            namespace,
+		Version:         repo.GetVersion(namespace),
+		CPrefix:         repo.GetCPrefix(namespace),
+		Includes:        repo.GetImmediateDependencies(namespace),
+		SharedLibraries: repo.GetSharedLibrary(namespace),
+	}
+
+	n := repo.GetNumInfos(namespace)
+	for i := 0; i < n; i++ {
+		info := repo.GetInfo(namespace, i)
+		switch info.Type {
+		case gi.Enum, gi.Flags:
+			enum, _ := info.AsEnum()
+			ns.Enums = append(ns.Enums, enumFromInfo(enum, ns.CPrefix))
+		case gi.Object:
+			obj, _ := info.AsObject()
+			ns.Classes = append(ns.Classes, classFromInfo(obj, ns.CPrefix))
+		case gi.Interface:
+			iface, _ := info.AsInterface()
+			ns.Interfaces = append(ns.Interfaces, interfaceFromInfo(iface, ns.CPrefix))
+		case gi.Constant:
+			constant, _ := info.AsConstant()
+			ns.Constants = append(ns.Constants, constantFromInfo(constant, ns.CPrefix))
+		case gi.Function:
+			fn, _ := info.AsFunction()
+			ns.Functions = append(ns.Functions, callableFromInfo(fn))
+		}
+		info.Free()
+	}
+
+	return ns, nil
+}
+
+func enumFromInfo(info *gi.EnumInfo, prefix string) *model.Enumeration {
+	enum := &model.Enumeration{
+		Name:       info.GetName(),
+		CType:      prefix + info.GetName(),
+		Flags:      info.Type == gi.Flags,
+		Deprecated: info.IsDeprecated(),
+	}
+	n := info.GetNValues()
+	for i := 0; i < n; i++ {
+		value := info.GetValue(i)
+		enum.Values = append(enum.Values, &model.Member{Name: value.GetName(), Value: value.GetValue()})
+		value.Free()
+	}
+	return enum
+}
+
+func classFromInfo(info *gi.ObjectInfo, prefix string) *model.Class {
+	obj := &model.Class{
+		Name:        info.GetName(),
+		Namespace:   info.GetNamespace(),
+		CType:       prefix + info.GetName(),
+		Abstract:    info.IsAbstract(),
+		Fundamental: info.IsFundamental(),
+		Deprecated:  info.IsDeprecated(),
+	}
+	if parent := info.GetParent(); parent != nil {
+		// like the .gir frontend, fundamental types are the roots of
+		// their hierarchies
+		if !obj.Fundamental {
+			obj.Parent = model.QualifiedName(parent.GetNamespace(), parent.GetName())
+		}
+		parent.Free()
+	}
+	n := info.GetNInterfaces()
+	for i := 0; i < n; i++ {
+		iface := info.GetInterface(i)
+		obj.Interfaces = append(obj.Interfaces, model.QualifiedName(iface.GetNamespace(), iface.GetName()))
+		iface.Free()
+	}
+	n = info.GetNProperties()
+	for i := 0; i < n; i++ {
+		prop := info.GetProperty(i)
+		obj.Properties = append(obj.Properties, propertyFromInfo(prop))
+		prop.Free()
+	}
+	n = info.GetNMethods()
+	for i := 0; i < n; i++ {
+		method := info.GetMethod(i)
+		obj.Methods = append(obj.Methods, callableFromInfo(method))
+		method.Free()
+	}
+	n = info.GetNSignals()
+	for i := 0; i < n; i++ {
+		signal := info.GetSignal(i)
+		obj.Signals = append(obj.Signals, signalFromInfo(signal))
+		signal.Free()
+	}
+	return obj
+}
+
+func interfaceFromInfo(info *gi.InterfaceInfo, prefix string) *model.Interface {
+	iface := &model.Interface{
+		Name:       info.GetName(),
+		Namespace:  info.GetNamespace(),
+		CType:      prefix + info.GetName(),
+		Deprecated: info.IsDeprecated(),
+	}
+	n := info.GetNPrerequisites()
+	for i := 0; i < n; i++ {
+		prereq := info.GetPrerequisite(i)
+		iface.Prerequisites = append(iface.Prerequisites, model.QualifiedName(prereq.GetNamespace(), prereq.GetName()))
+		prereq.Free()
+	}
+	n = info.GetNProperties()
+	for i := 0; i < n; i++ {
+		prop := info.GetProperty(i)
+		iface.Properties = append(iface.Properties, propertyFromInfo(prop))
+		prop.Free()
+	}
+	n = info.GetNMethods()
+	for i := 0; i < n; i++ {
+		method := info.GetMethod(i)
+		iface.Methods = append(iface.Methods, callableFromInfo(method))
+		method.Free()
+	}
+	n = info.GetNSignals()
+	for i := 0; i < n; i++ {
+		signal := info.GetSignal(i)
+		iface.Signals = append(iface.Signals, signalFromInfo(signal))
+		signal.Free()
+	}
+	return iface
+}
+
+func constantFromInfo(info *gi.ConstantInfo, prefix string) *model.Constant {
+	typ := info.GetType()
+	defer typ.Free()
+
+	// typelibs don't keep the C names of constants, but they follow a
+	// fixed pattern
+	return &model.Constant{
+		Name:        info.GetName(),
+		CIdentifier: strings.ToUpper(symbolPrefix(prefix)) + "_" + info.GetName(),
+		Deprecated:  info.IsDeprecated(),
+		Type:        typeFromInfo(typ),
+		Value:       fmt.Sprint(info.GetValue()),
+	}
+}
+
+// The patterns g-ir-scanner uses to split identifier prefixes into words.
+var (
+	lowerUpper = regexp.MustCompile(`([^A-Z])([A-Z])`)
+	upperWord  = regexp.MustCompile(`([A-Z][A-Z])([A-Z][0-9a-z])`)
+	leadUpper  = regexp.MustCompile(`^([A-Z])([A-Z])`)
+)
+
+// symbolPrefix turns the identifier prefix of a namespace into its symbol
+// prefix the way g-ir-scanner does when it isn't given one, i.e.
+// "GdkPixbuf" into "gdk_pixbuf".
+func symbolPrefix(prefix string) string {
+	prefix = lowerUpper.ReplaceAllString(prefix, "${1}_${2}")
+	prefix = upperWord.ReplaceAllString(prefix, "${1}_${2}")
+	if leadUpper.MatchString(prefix) {
+		prefix = prefix[:1] + "_" + prefix[1:]
+	}
+	return strings.ToLower(prefix)
+}
+
+func propertyFromInfo(info *gi.PropertyInfo) *model.Property {
+	typ := info.GetType()
+	defer typ.Free()
+
+	flags := info.GetFlags()
+	return &model.Property{
+		Name:          info.GetName(),
+		Deprecated:    info.IsDeprecated(),
+		Type:          typeFromInfo(typ),
+		Transfer:      model.Transfer(info.GetOwnershipTransfer()),
+		Readable:      flags.Readable,
+		Writable:      flags.Writable,
+		Construct:     flags.Construct,
+		ConstructOnly: flags.ConstructOnly,
+	}
+}
+
+func callableFromInfo(info *gi.FunctionInfo) *model.Callable {
+	fn := signatureFromInfo(&info.CallableInfo)
+	fn.Symbol = info.GetSymbol()
+	fn.Flags = model.FunctionFlags(info.GetFlags())
+	return fn
+}
+
+func signalFromInfo(info *gi.SignalInfo) *model.Callable {
+	return signatureFromInfo(&info.CallableInfo)
+}
+
+// signatureFromInfo fills in what all callables have in common.
+func signatureFromInfo(info *gi.CallableInfo) *model.Callable {
+	ret := info.GetReturnType()
+	defer ret.Free()
+
+	fn := &model.Callable{
+		Name:           info.GetName(),
+		Deprecated:     info.IsDeprecated(),
+		Return:         typeFromInfo(ret),
+		ReturnTransfer: model.Transfer(info.GetCallerOwns()),
+		MayReturnNull:  info.MayReturnNull(),
+	}
+	n := info.GetNArgs()
+	for i := 0; i < n; i++ {
+		arg := info.GetArg(i)
+		fn.Params = append(fn.Params, paramFromInfo(arg))
+		arg.Free()
+	}
+	return fn
+}
+
+func paramFromInfo(info *gi.ArgInfo) *model.Param {
+	typ := info.GetType()
+	defer typ.Free()
+
+	return &model.Param{
+		Name:            info.GetName(),
+		Direction:       model.Direction(info.GetDirection()),
+		Transfer:        model.Transfer(info.GetOwnershipTransfer()),
+		Nullable:        info.MayBeNull(),
+		Optional:        info.IsOptional(),
+		CallerAllocates: info.IsCallerAllocates(),
+		Skip:            info.IsSkip(),
+		Type:            typeFromInfo(typ),
+	}
+}
+
+func typeFromInfo(info *gi.TypeInfo) *model.TypeRef {
+	typ := &model.TypeRef{Tag: model.TypeTag(info.GetTag()), Pointer: info.IsPointer()}
+	if typ.Tag == model.InterfaceTag {
+		iface := info.GetInterface()
+		typ.Interface = model.QualifiedName(iface.GetNamespace(), iface.GetName())
+		iface.Free()
+	}
+	return typ
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dradtke/go-gi/gi"
+	"github.com/dradtke/go-gi/model"
+)
+
+// TestTypelibLeaks checks that loading a namespace from its typelib and
+// generating it frees every info it looks at, which is what -leakcheck
+// checks too.
+func TestTypelibLeaks(t *testing.T) {
+	namespaces, err := model.LoadAll(TypelibLoader{}, "GObject", "2.0")
+	if err != nil {
+		t.Skip("GObject typelib isn't installed: " + err.Error())
+	}
+	g := newGenerator(t, namespaces)
+	if _, _, err := g.Generate(namespaces.DependencyOrder("GObject")); err != nil {
+		t.Fatal(err)
+	}
+	if n := outstandingInfos(); n > 0 {
+		t.Errorf("%d introspection infos were never freed", n)
+	}
+}
+
+// TestGoldenTypelib builds GoTest and scans it into a typelib, if the
+// GObject development files, g-ir-scanner and g-ir-compiler are installed,
+// and checks that the bindings generated from the typelib match the golden
+// output of TestGolden, so that both frontends have to agree. Typelibs
+// don't record which header to include, any docs or the versions things
+// were added in, and the real GObject is used instead of the cut-down one,
+// so the docs are taken from testdata/gotest the way -docs does, and only
+// the code after the header of the GoTest package is compared. Without
+// the docs, nothing can be gated, so everything has to end up in the
+// untagged file.
+func TestGoldenTypelib(t *testing.T) {
+	lib := buildGoTest(t)
+	for _, tool := range []string{"g-ir-scanner", "g-ir-compiler"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skip(tool + " not found")
+		}
+	}
+	include, err := filepath.Abs(testdataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gir := filepath.Join(lib, "GoTest-1.0.gir")
+	run(t, "g-ir-scanner", "--quiet", "--warn-all",
+		"--namespace=GoTest", "--nsversion=1.0",
+		"--identifier-prefix=GoTest", "--symbol-prefix=go_test",
+		"--include=GObject-2.0", "--c-include=gotest.h",
+		"--library=gotest", "--library-path="+lib,
+		"-I"+include, "--output="+gir,
+		filepath.Join(include, "gotest.h"), filepath.Join(include, "gotest.c"))
+	run(t, "g-ir-compiler", "--output="+filepath.Join(lib, "GoTest-1.0.typelib"), gir)
+
+	gi.PrependSearchPath(lib)
+	gi.PrependLibraryPath(lib)
+	generate := func(t *testing.T, docs bool) map[string][]byte {
+		namespaces, err := model.LoadAll(TypelibLoader{}, "GoTest", "1.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs {
+			loader := model.GIRLoader{Path: []string{testdataDir}}
+			girs := make(model.Namespaces)
+			for _, ns := range namespaces {
+				if from, err := loader.Load(ns.Name, ns.Version); err == nil {
+					girs[ns.Name] = from
+				}
+			}
+			girs.ResolveAliases()
+			for name, from := range girs {
+				model.CopyDocs(namespaces[name], from)
+			}
+		}
+		g := newGenerator(t, namespaces)
+		g.Overrides = os.DirFS(filepath.Join("testdata", "overrides"))
+		files, _, err := g.Generate([]string{"GoTest"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	t.Run("docs", func(t *testing.T) {
+		golden := make(map[string][]byte)
+		for name, data := range readTree(t, goldenDir) {
+			if strings.HasPrefix(name, "gotest/") {
+				golden[name] = data
+			}
+		}
+		compareTrees(t, golden, generate(t, true), afterHeader)
+	})
+	t.Run("no docs", func(t *testing.T) {
+		for name := range generate(t, false) {
+			if name != "gotest/gotest.go" {
+				t.Errorf("%s is generated, but nothing should be gated without versions", name)
+			}
+		}
+	})
+}
+
+// afterHeader returns the code of a generated file after its import "C".
+func afterHeader(file []byte) []byte {
+	if i := bytes.Index(file, []byte("import \"C\"\n")); i >= 0 {
+		return file[i+len("import \"C\"\n"):]
+	}
+	return file
+}
+
+// run runs a command, failing the test if it doesn't succeed.
+func run(t *testing.T, name string, args ...string) {
+	t.Helper()
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		t.Fatalf("%s: %v\n%s", name, err, out)
+	}
+}
@@ -1,30 +1,28 @@
 package main
 
-/*
-#cgo pkg-config: glib-2.0
-#include <glib.h>
-*/
-import "C"
+import (
+	"github.com/dradtke/go-gi/model"
+)
 
-const GoVoidPointer = "interface{}"
+const GoVoidPointer = "unsafe.Pointer"
 const CVoidPointer = "gpointer"
 
-var TypeTagToGo = map[TypeTag] string {
-	VoidTag:     "",
-	BooleanTag:  "bool",
-	Int8Tag:     "int8",
-	Uint8Tag:    "uint8",
-	Int16Tag:    "int16",
-	Uint16Tag:   "uint16",
-	Int32Tag:    "int32",
-	Uint32Tag:   "uint32",
-	Int64Tag:    "int64",
-	Uint64Tag:   "uint64",
-	FloatTag:    "float32",
-	DoubleTag:   "float64",
-	GTypeTag:    "int",
-	Utf8Tag:     "string",
-	FilenameTag: "string",
+var TypeTagToGo = map[model.TypeTag] string {
+	model.VoidTag:     "",
+	model.BooleanTag:  "bool",
+	model.Int8Tag:     "int8",
+	model.Uint8Tag:    "uint8",
+	model.Int16Tag:    "int16",
+	model.Uint16Tag:   "uint16",
+	model.Int32Tag:    "int32",
+	model.Uint32Tag:   "uint32",
+	model.Int64Tag:    "int64",
+	model.Uint64Tag:   "uint64",
+	model.FloatTag:    "float32",
+	model.DoubleTag:   "float64",
+	model.GTypeTag:    "int",
+	model.Utf8Tag:     "string",
+	model.FilenameTag: "string",
 	// TODO: figure out how to do complex types
 	/*
 	ArrayTag
@@ -38,20 +36,20 @@ var TypeTagToGo = map[TypeTag] string {
 	//UnicharTag
 }
 
-var TypeTagToC = map[TypeTag] string {
-	VoidTag:     "",
-	BooleanTag:  "gboolean",
-	Int8Tag:     "gint8",
-	Uint8Tag:    "guint8",
-	Int16Tag:    "gint16",
-	Uint16Tag:   "guint16",
-	Int32Tag:    "gint32",
-	Uint32Tag:   "guint32",
-	Int64Tag:    "gint64",
-	Uint64Tag:   "guint64",
-	FloatTag:    "gfloat",
-	DoubleTag:   "gdouble",
-	GTypeTag:    "gint",
-	Utf8Tag:     "gchar",
-	FilenameTag: "gchar",
+var TypeTagToC = map[model.TypeTag] string {
+	model.VoidTag:     "",
+	model.BooleanTag:  "gboolean",
+	model.Int8Tag:     "gint8",
+	model.Uint8Tag:    "guint8",
+	model.Int16Tag:    "gint16",
+	model.Uint16Tag:   "guint16",
+	model.Int32Tag:    "gint32",
+	model.Uint32Tag:   "guint32",
+	model.Int64Tag:    "gint64",
+	model.Uint64Tag:   "guint64",
+	model.FloatTag:    "gfloat",
+	model.DoubleTag:   "gdouble",
+	model.GTypeTag:    "GType",
+	model.Utf8Tag:     "gchar",
+	model.FilenameTag: "gchar",
 }
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/dradtke/go-gi/model"
+)
+
+// VersionGates decides which build tags guard code that needs a recent
+// version of its library. Every version of a namespace that added
+// something gets a tag, i.e. gtk_3_22 for GTK 3.22, and building with it
+// leaves out everything added after 3.22, so bindings generated against
+// the newest version still build against older ones. Without any tags,
+// everything is built.
+type VersionGates struct {
+	versions map[string][]string // known versions of each namespace, oldest first
+}
+
+func NewVersionGates(namespaces model.Namespaces) *VersionGates {
+	gates := &VersionGates{versions: make(map[string][]string)}
+	for name, ns := range namespaces {
+		seen := make(map[string]bool)
+		add := func(version string) {
+			if version != "" && !seen[version] {
+				seen[version] = true
+				gates.versions[name] = append(gates.versions[name], version)
+			}
+		}
+		addCallable := func(fn *model.Callable) {
+			// these are never generated, so never gated
+			if fn.Unintrospectable == "" {
+				add(fn.Version)
+			}
+		}
+		for _, enum := range ns.Enums {
+			add(enum.Version)
+		}
+		for _, obj := range ns.Classes {
+			add(obj.Version)
+			for _, method := range obj.Methods {
+				addCallable(method)
+			}
+		}
+		for _, iface := range ns.Interfaces {
+			add(iface.Version)
+			for _, method := range iface.Methods {
+				addCallable(method)
+			}
+		}
+		for _, fn := range ns.Functions {
+			addCallable(fn)
+		}
+		versions := gates.versions[name]
+		sort.Slice(versions, func(i, j int) bool { return model.CompareVersions(versions[i], versions[j]) < 0 })
+	}
+	return gates
+}
+
+// Tag returns the build tag for version of namespace, or "" if anything
+// added in it can be built against every version that's known of.
+func (gates *VersionGates) Tag(namespace, version string) string {
+	if gates == nil || version == "" || len(gates.older(namespace, version)) == 0 {
+		return ""
+	}
+	return versionTag(namespace, version)
+}
+
+// Constraint returns the //go:build expression for the code guarded by
+// tag, which leaves it out when building for an older version. A tag made
+// by joinTags needs both of the versions it joins.
+func (gates *VersionGates) Constraint(tag string) string {
+	for namespace, versions := range gates.versions {
+		for _, version := range versions {
+			single := versionTag(namespace, version)
+			if tag != single && !strings.HasPrefix(tag, single+"_") {
+				continue
+			}
+			var terms []string
+			for _, v := range gates.older(namespace, version) {
+				terms = append(terms, "!"+versionTag(namespace, v))
+			}
+			if tag == single {
+				return strings.Join(terms, " && ")
+			}
+			if rest := gates.Constraint(strings.TrimPrefix(tag, single+"_")); rest != "" {
+				return strings.Join(append(terms, rest), " && ")
+			}
+		}
+	}
+	return ""
+}
+
+// joinTags returns the tag of code that needs the versions of both a type
+// and a member of it from another namespace.
+func joinTags(typeTag, memberTag string) string {
+	switch {
+	case typeTag == "":
+		return memberTag
+	case memberTag == "":
+		return typeTag
+	}
+	return typeTag + "_" + memberTag
+}
+
+func (gates *VersionGates) older(namespace, version string) []string {
+	var older []string
+	for _, v := range gates.versions[namespace] {
+		if model.CompareVersions(v, version) < 0 {
+			older = append(older, v)
+		}
+	}
+	return older
+}
+
+func versionTag(namespace, version string) string {
+	return strings.ToLower(namespace) + "_" + strings.ReplaceAll(version, ".", "_")
+}
+
+// Code is what's generated for a namespace, split up by the build tag
+// that guards it. Code that builds against any version is under "".
+type Code struct {
+	gates *VersionGates
+	Files map[string]*CodeFile
+}
+
+// CodeFile is the code going into a single file.
+type CodeFile struct {
+	bytes.Buffer
+	Imports map[string]bool // packages of other namespaces referenced
+}
+
+func NewCode(gates *VersionGates) *Code {
+	return &Code{gates: gates, Files: make(map[string]*CodeFile)}
+}
+
+// For returns the file for something added in version of namespace.
+func (code *Code) For(namespace, version string) *CodeFile {
+	return code.Tagged(code.gates.Tag(namespace, version))
+}
+
+// ForMember returns the file for a member of a type, which needs both the
+// version of namespace it was added in and the version of typeNamespace
+// the type was. Within a namespace, the newer of the two is what counts;
+// if they're from different namespaces and both need a recent version,
+// the file is guarded by both, i.e. gtk/gtk_3_22_gobject_2_70.go.
+func (code *Code) ForMember(typeNamespace, typeVersion, namespace, version string) *CodeFile {
+	tag := code.gates.Tag(namespace, version)
+	if namespace != typeNamespace {
+		return code.Tagged(joinTags(code.gates.Tag(typeNamespace, typeVersion), tag))
+	}
+	if tag == "" || model.CompareVersions(version, typeVersion) <= 0 {
+		return code.For(typeNamespace, typeVersion)
+	}
+	return code.Tagged(tag)
+}
+
+// Tagged returns the file guarded by tag.
+func (code *Code) Tagged(tag string) *CodeFile {
+	file, ok := code.Files[tag]
+	if !ok {
+		file = &CodeFile{Imports: make(map[string]bool)}
+		code.Files[tag] = file
+	}
+	return file
+}